@@ -2,12 +2,10 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/euclidstellar/gollora/internal/ai"
+	"github.com/euclidstellar/gollora/internal/policy"
 	"github.com/euclidstellar/gollora/internal/utils"
 	"github.com/euclidstellar/gollora/internal/models"
 )
@@ -22,95 +20,108 @@ func NewResultAggregator(config *models.Config) *ResultAggregator {
     }
 }
 
-func (ra *ResultAggregator) AggregateResults(result *models.AnalysisResult) *models.AnalysisResult {
+// aiScoreCache outlives a single analysis so findings that persist across
+// pushes are not re-scored.
+var aiScoreCache = ai.NewScoreCache(0)
+
+func (ra *ResultAggregator) AggregateResults(ctx context.Context, result *models.AnalysisResult) *models.AnalysisResult {
     utils.LogWithLocation(utils.Info, "Aggregating results")
 
+    // Merge duplicates first so each finding is scored once.
+    result = ra.removeDuplicates(result)
+
     // AI-powered severity scoring if enabled
     if ra.config.AI.Enabled {
-        ra.scoreSeverityWithAI(result)
+        ra.scoreSeverityWithAI(ctx, result)
     }
 
-    result = ra.removeDuplicates(result)
     ra.prioritizeIssues(result)
 
     return result
 }
 
-func (ra *ResultAggregator) scoreSeverityWithAI(result *models.AnalysisResult) {
-    aiClient := ai.NewClient(ra.config)
-    ctx := context.Background()
+func (ra *ResultAggregator) scoreSeverityWithAI(ctx context.Context, result *models.AnalysisResult) {
+    var candidates []models.CodeIssue
+    for _, issue := range result.Issues {
+        if ra.scoreable(issue) {
+            candidates = append(candidates, issue)
+        }
+    }
+    if len(candidates) == 0 {
+        return
+    }
+
+    scorer := ai.NewSeverityScorer(ai.NewClient(ra.config), aiScoreCache,
+        ra.config.AI.ScoringBatchSize, ra.config.AI.MaxScoringCalls)
+
+    scores, stats, err := scorer.Score(ctx, candidates)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "AI severity scoring stopped early: %v", err)
+    }
+    utils.LogWithLocation(utils.Info, "AI severity scoring: %d scored, %d cached, %d skipped, %d invalid replies in %d calls",
+        stats.Scored, stats.Cached, stats.Skipped, stats.Invalid, stats.Calls)
+
+    if len(scores) == 0 {
+        return
+    }
 
     for i := range result.Issues {
         issue := &result.Issues[i]
-        // Don't re-score issues that already came from the AI
-        if issue.Tool == "Gemini" {
+        score, ok := scores[issue.Fingerprint]
+        if !ok || !ra.scoreable(*issue) {
             continue
         }
-
-        prompt := fmt.Sprintf(`You are a code quality expert. Analyze the following issue and score its severity.
-Code Issue: "%s"
-File: %s
-Line: %d
-Code Snippet:
----
-%s
----
-
-Based on the context, rate the severity as one of: "CRITICAL", "ERROR", "WARNING", "INFO".
-A hardcoded secret is CRITICAL. A syntax error is an ERROR. A stylistic issue is INFO.
-Respond with a JSON object containing "new_severity" and "reason".
-Example: {"new_severity": "WARNING", "reason": "This could lead to a null pointer exception if the variable is not initialized."}
-
-Your response:`, issue.Description, issue.File, issue.Line, issue.Code)
-
-        response, err := aiClient.GenerateContent(ctx, prompt)
-        if err != nil {
-            utils.LogWithLocation(utils.Warn, "AI severity scoring failed for issue: %v", err)
-            continue
-        }
-
-        var scoringResult struct {
-            NewSeverity string `json:"new_severity"`
-            Reason      string `json:"reason"`
+        if score.Severity != issue.Severity {
+            utils.LogWithLocation(utils.Debug, "AI re-scored issue severity from %s to %s. Reason: %s", issue.Severity, score.Severity, score.Justification)
+            if issue.OriginalSeverity == "" {
+                issue.OriginalSeverity = issue.Severity
+            }
+            issue.Severity = score.Severity
         }
+        issue.AIJustification = score.Justification
+    }
+    result.RecalculateSummary()
+}
 
-        response = strings.TrimPrefix(response, "```json")
-        response = strings.TrimSuffix(response, "```")
-        if err := json.Unmarshal([]byte(response), &scoringResult); err == nil {
-            utils.LogWithLocation(utils.Info, "AI re-scored issue severity from %s to %s. Reason: %s", issue.Severity, scoringResult.NewSeverity, scoringResult.Reason)
-            issue.Severity = models.IssueSeverity(scoringResult.NewSeverity)
-            // Optionally, add the reason to the description
-            issue.Description = fmt.Sprintf("%s\n\n**AI Justification**: %s", issue.Description, scoringResult.Reason)
-        }
+// scoreable reports whether AI scoring may change the issue's severity. AI
+// findings and suppressed issues are left alone, as are severities the
+// severity policy set unless ai.override_policy allows it.
+func (ra *ResultAggregator) scoreable(issue models.CodeIssue) bool {
+    if issue.Tool == "Gemini" || issue.Status == models.StatusSuppressed {
+        return false
     }
+    return !issue.PolicySeverity || ra.config.AI.OverridePolicy
 }
 
+// removeDuplicates merges findings that several tools report for the same
+// problem; see policy.Deduplicator for the clustering rules.
 func (ra *ResultAggregator) removeDuplicates(result *models.AnalysisResult) *models.AnalysisResult {
-    issueMap := make(map[string]models.CodeIssue)
-    
-    for _, issue := range result.Issues {
-        key := issue.File + ":" + string(issue.Line) + ":" + issue.Description
-        
-        if existing, ok := issueMap[key]; ok {
-            if ra.getSeverityWeight(issue.Severity) > ra.getSeverityWeight(existing.Severity) {
-                issueMap[key] = issue
-            }
-        } else {
-            issueMap[key] = issue
-        }
+    dedup, err := policy.NewDeduplicator(ra.config.Deduplication)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "Invalid deduplication config, using defaults: %v", err)
+        dedup, _ = policy.NewDeduplicator(models.DeduplicationConfig{})
     }
 
+    issues, removed := dedup.Merge(result.Issues)
+
     dedupedResult := models.NewAnalysisResult(result.Event)
+    dedupedResult.ID = result.ID
     dedupedResult.AnalyzedAt = result.AnalyzedAt
     dedupedResult.CompletedAt = result.CompletedAt
     dedupedResult.Duration = result.Duration
     dedupedResult.OutputFiles = result.OutputFiles
-
-    for _, issue := range issueMap {
+    dedupedResult.QualityGate = result.QualityGate
+    dedupedResult.Risk = result.Risk
+    dedupedResult.History = result.History
+    dedupedResult.Dependencies = result.Dependencies
+    dedupedResult.Summary.FileCount = result.Summary.FileCount
+    dedupedResult.Summary.DependencyGraph = result.Summary.DependencyGraph
+
+    for _, issue := range issues {
         dedupedResult.AddIssue(issue)
     }
     
-    utils.LogWithLocation(utils.Info, "Removed %d duplicate issues", len(result.Issues)-len(dedupedResult.Issues))
+    utils.LogWithLocation(utils.Info, "Merged %d duplicate issues", removed)
     
     return dedupedResult
 }
@@ -142,20 +153,7 @@ func (ra *ResultAggregator) prioritizeIssues(result *models.AnalysisResult) {
 
 
 func (ra *ResultAggregator) getSeverityWeight(severity models.IssueSeverity) int {
-    switch severity {
-    case models.Critical:
-        return 4
-    case models.Error:
-        return 3
-    case models.Warning:
-        return 2
-    case models.Info:
-        return 1
-    case models.Hint:
-        return 0
-    default:
-        return -1
-    }
+    return severity.Rank()
 }
 
 func (ra *ResultAggregator) getTypeWeight(issueType models.IssueType) int {
@@ -178,34 +176,24 @@ func (ra *ResultAggregator) getTypeWeight(issueType models.IssueType) int {
 }
 
 
+// FilterIssuesByThreshold returns the active issues at or above threshold,
+// which is a severity name, "none" or "" (everything).
 func (ra *ResultAggregator) FilterIssuesByThreshold(result *models.AnalysisResult, threshold string) []models.CodeIssue {
+    t, ok := models.ParseThreshold(threshold)
+    if !ok {
+        utils.LogWithLocation(utils.Warn, "Unknown severity threshold %q, not filtering", threshold)
+    }
+
     var filteredIssues []models.CodeIssue
     
     for _, issue := range result.Issues {
-        severityValue := severityToValue(string(issue.Severity))
-        thresholdValue := severityToValue(threshold)
-        
-        if severityValue >= thresholdValue {
+        if issue.Status == models.StatusSuppressed {
+            continue
+        }
+        if t.Allows(issue.Severity) {
             filteredIssues = append(filteredIssues, issue)
         }
     }
     
     return filteredIssues
 }
-
-func severityToValue(severity string) int {
-    switch strings.ToLower(severity) {
-    case "critical":
-        return 4
-    case "high":
-        return 3
-    case "medium":
-        return 2
-    case "low":
-        return 1
-    case "info":
-        return 0
-    default:
-        return 0
-    }
-}
\ No newline at end of file
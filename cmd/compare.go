@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/compare"
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// runCompare implements "gollora compare <base.json> <head.json>": it diffs
+// two results written by the JSON exporter and writes the comparison in
+// each requested format. It needs no repository or network access.
+func runCompare(config *models.Config, args []string) {
+    fs := flag.NewFlagSet("compare", flag.ExitOnError)
+    formats := fs.String("format", "markdown,json,html", "Comma-separated output formats: markdown, json, html")
+    outDir := fs.String("output-dir", ".", "Directory for the comparison reports")
+    fs.Usage = func() {
+        fmt.Fprintf(fs.Output(), "Usage: %s compare [flags] <base.json> <head.json>\n", filepath.Base(os.Args[0]))
+        fs.PrintDefaults()
+    }
+    fs.Parse(args)
+
+    if fs.NArg() != 2 {
+        fs.Usage()
+        os.Exit(1)
+    }
+
+    comparison, err := compare.Files(fs.Arg(0), fs.Arg(1))
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Comparison failed: %v", err)
+        os.Exit(1)
+    }
+
+    if err := os.MkdirAll(*outDir, 0755); err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to create output directory: %v", err)
+        os.Exit(1)
+    }
+
+    renderer := newRenderer(config)
+    base := filepath.Join(*outDir, fmt.Sprintf("compare-%s-%s", comparison.Base.ID, comparison.Head.ID))
+    for _, format := range strings.Split(*formats, ",") {
+        switch format = strings.TrimSpace(format); format {
+        case "json":
+            path := base + ".json"
+            if err := utils.FormatToJSON(comparison, path); err != nil {
+                utils.LogWithLocation(utils.Error, "Failed to export comparison to JSON: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "JSON comparison saved to: %s", path)
+
+        case "markdown":
+            path := base + ".md"
+            if err := utils.FormatComparisonToMarkdown(comparison, renderer, path); err != nil {
+                utils.LogWithLocation(utils.Error, "Failed to export comparison to Markdown: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "Markdown comparison saved to: %s", path)
+
+        case "html":
+            path := base + ".html"
+            if err := utils.FormatComparisonToHTML(comparison, path); err != nil {
+                utils.LogWithLocation(utils.Error, "Failed to export comparison to HTML: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "HTML comparison saved to: %s", path)
+
+        case "":
+        default:
+            utils.LogWithLocation(utils.Warn, "Unsupported comparison format: %s", format)
+        }
+    }
+
+    utils.LogWithLocation(utils.Info, "Comparison complete: %d new, %d fixed, %d unchanged issues",
+        len(comparison.New), len(comparison.Fixed), len(comparison.Unchanged))
+}
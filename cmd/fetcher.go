@@ -118,7 +118,7 @@ func (cf *CodeFetcher) FetchCode(ctx context.Context, event models.WebhookEvent)
             continue
         }
         
-        language := determineLanguage(file)
+        language := models.DetectLanguageFromFile(file)
         
         fileToAnalyze := models.FileToAnalyze{
             Path:     file,
@@ -160,58 +160,3 @@ func shouldSkipFile(file string, tempDir string) bool {
     
     return false
 }
-
-func determineLanguage(filename string) string {
-    ext := strings.ToLower(filepath.Ext(filename))
-    
-    switch ext {
-    case ".go":
-        return "go"
-    case ".js":
-        return "javascript"
-    case ".ts":
-        return "typescript"
-    case ".jsx":
-        return "javascript"
-    case ".tsx":
-        return "typescript"
-    case ".py":
-        return "python"
-    case ".java":
-        return "java"
-    case ".rb":
-        return "ruby"
-    case ".php":
-        return "php"
-    case ".c", ".h":
-        return "c"
-    case ".cpp", ".hpp", ".cc":
-        return "cpp"
-    case ".cs":
-        return "csharp"
-    case ".rs":
-        return "rust"
-    case ".swift":
-        return "swift"
-    case ".kt", ".kts":
-        return "kotlin"
-    case ".sh":
-        return "shell"
-    case ".yaml", ".yml":
-        return "yaml"
-    case ".json":
-        return "json"
-    case ".md":
-        return "markdown"
-    case ".html", ".htm":
-        return "html"
-    case ".css":
-        return "css"
-    case ".sql":
-        return "sql"
-    case ".dart":
-        return "dart"
-    default:
-        return "unknown"
-    }
-}
\ No newline at end of file
@@ -15,7 +15,10 @@ import (
 	"time"
 
 	"github.com/euclidstellar/gollora/internal/agent"
+	"github.com/euclidstellar/gollora/internal/export"
 	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/policy"
+	"github.com/euclidstellar/gollora/internal/report"
 	"github.com/euclidstellar/gollora/internal/utils"
 	"gopkg.in/yaml.v3"
 )
@@ -34,6 +37,7 @@ var (
     baseCommit  = flag.String("base-commit", "", "Base commit for comparison")
     headCommit  = flag.String("head-commit", "", "Head commit for comparison")
     outputDir   = flag.String("output-dir", "", "Directory for analysis output")
+    streamPath  = flag.String("stream", "", "Write findings to this file as NDJSON while the analysis runs")
     
     // Event flags
     eventType     = flag.String("event-type", "", "Event type (push, pull_request)")
@@ -42,7 +46,17 @@ var (
     prURL         = flag.String("pr-url", "", "Pull request URL")
 )
 
+// exitGateFailed is the exit code used when the analysis ran but the
+// quality gate failed; exit code 1 is reserved for runtime errors.
+const exitGateFailed = 2
+
 func main() {
+    os.Exit(run())
+}
+
+// run executes the selected mode and returns the process exit code. main
+// exits only after run returns, so deferred closes always happen.
+func run() int {
     flag.Parse()
     
     if err := os.MkdirAll(*logDir, 0755); err != nil {
@@ -60,7 +74,7 @@ func main() {
     config, toolsConfig, err := loadConfigurations(*configPath, *toolsPath)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Failed to load configurations: %v", err)
-        os.Exit(1)
+        return 1
     }
     
     if *port > 0 {
@@ -69,18 +83,21 @@ func main() {
 
     loadAPIKeysFromEnv(config)
 
-    if *serverMode {
+    if flag.Arg(0) == "compare" {
+        runCompare(config, flag.Args()[1:])
+    } else if *serverMode {
         runServer(config, toolsConfig)
     } else if *analyzeMode {
-        runAnalyze(config, toolsConfig)
+        return runAnalyze(config, toolsConfig)
     } else if *qaMode {
-        runQA(config)
+        return runQA(config)
     } else if *repoPath != "" {
-        runDirectAnalysis(config, toolsConfig)
+        return runDirectAnalysis(config, toolsConfig)
     } else {
         flag.Usage()
-        os.Exit(1)
+        return 1
     }
+    return 0
 }
 
 func loadConfigurations(configPath, toolsPath string) (*models.Config, *models.AnalysisToolsConfig, error) {
@@ -103,6 +120,45 @@ func loadConfigurations(configPath, toolsPath string) (*models.Config, *models.A
     if err := yaml.Unmarshal(toolsData, &toolsConfig); err != nil {
         return nil, nil, fmt.Errorf("failed to parse tools config file: %v", err)
     }
+
+    if _, err := policy.NewSeverityPolicy(toolsConfig.SeverityPolicy); err != nil {
+        return nil, nil, fmt.Errorf("invalid tools config: %v", err)
+    }
+
+    if err := policy.ValidateGate(config.QualityGate); err != nil {
+        return nil, nil, fmt.Errorf("invalid config: %v", err)
+    }
+
+    if _, err := report.NewRenderer(config.Templates); err != nil {
+        return nil, nil, fmt.Errorf("invalid config: %v", err)
+    }
+
+    if err := export.Validate(config.Export); err != nil {
+        return nil, nil, fmt.Errorf("invalid config: %v", err)
+    }
+
+    if _, err := policy.NewDeduplicator(config.Deduplication); err != nil {
+        return nil, nil, fmt.Errorf("invalid config: %v", err)
+    }
+
+    // Inline comments historically covered warning and above, and the
+    // summary table stopped at info.
+    if config.Thresholds.Comments == "" {
+        config.Thresholds.Comments = "warning"
+    }
+    if config.Thresholds.Summary == "" {
+        config.Thresholds.Summary = "info"
+    }
+    for name, value := range map[string]string{
+        "comments": config.Thresholds.Comments,
+        "summary":  config.Thresholds.Summary,
+        "gate":     config.Thresholds.Gate,
+        "export":   config.Thresholds.Export,
+    } {
+        if _, ok := models.ParseThreshold(value); !ok {
+            return nil, nil, fmt.Errorf("invalid config: unknown thresholds.%s %q", name, value)
+        }
+    }
     
     return &config, &toolsConfig, nil
 }
@@ -127,6 +183,22 @@ func loadAPIKeysFromEnv(config *models.Config) {
     if apiKey := os.Getenv("AI_API_KEY"); apiKey != "" {
         config.AI.APIKey = apiKey
     }
+
+    if key := os.Getenv("AWS_ACCESS_KEY_ID"); key != "" {
+        config.Export.S3.AccessKey = key
+    }
+
+    if secret := os.Getenv("AWS_SECRET_ACCESS_KEY"); secret != "" {
+        config.Export.S3.SecretKey = secret
+    }
+
+    if token := os.Getenv("GOLLORA_API_TOKEN"); token != "" {
+        config.Server.APIToken = token
+    }
+
+    if key := os.Getenv("GOLLORA_REPORT_SIGNING_KEY"); key != "" {
+        config.Export.SigningKey = key
+    }
 }
 
 func runServer(config *models.Config, toolsConfig *models.AnalysisToolsConfig) {
@@ -163,11 +235,11 @@ func runServer(config *models.Config, toolsConfig *models.AnalysisToolsConfig) {
     utils.LogWithLocation(utils.Info, "Server stopped")
 }
 
-func runAnalyze(config *models.Config, toolsConfig *models.AnalysisToolsConfig) {
+func runAnalyze(config *models.Config, toolsConfig *models.AnalysisToolsConfig) int {
     if *eventType == "" || *repoURL == "" || *baseCommit == "" || *headCommit == "" {
         utils.LogWithLocation(utils.Error, "Missing required arguments for analyze mode")
         flag.Usage()
-        os.Exit(1)
+        return 1
     }
 
     event := models.WebhookEvent{
@@ -184,14 +256,18 @@ func runAnalyze(config *models.Config, toolsConfig *models.AnalysisToolsConfig)
     repoFullName := extractRepoFullNameFromURL(*repoURL)
     event.RepoFullName = repoFullName
 
-    runAnalysisProcess(context.Background(), config, toolsConfig, event)
+    result := runAnalysisProcess(context.Background(), config, toolsConfig, event)
+    if result == nil {
+        return 1
+    }
+    return gateExitCode(result.QualityGate)
 }
 
-func runDirectAnalysis(config *models.Config, toolsConfig *models.AnalysisToolsConfig) {
+func runDirectAnalysis(config *models.Config, toolsConfig *models.AnalysisToolsConfig) int {
     if *repoPath == "" || *baseCommit == "" || *headCommit == "" {
         utils.LogWithLocation(utils.Error, "Missing required arguments for direct analysis")
         flag.Usage()
-        os.Exit(1)
+        return 1
     }
     
     event := models.WebhookEvent{
@@ -200,10 +276,13 @@ func runDirectAnalysis(config *models.Config, toolsConfig *models.AnalysisToolsC
         BaseCommit: *baseCommit,
         HeadCommit: *headCommit,
     }
+    if branch, err := utils.GetCurrentBranch(*repoPath); err == nil {
+        event.Branch = branch
+    }
 
     outDir := *outputDir
     if outDir == "" {
-        outDir = filepath.Join(*repoPath, "code-review-output")
+        outDir = filepath.Join(*repoPath, export.DefaultOutputDir)
     }
 
     ctx := context.Background()
@@ -213,7 +292,7 @@ func runDirectAnalysis(config *models.Config, toolsConfig *models.AnalysisToolsC
     changedFiles, err := utils.GetChangedFiles(*repoPath, *baseCommit, *headCommit)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Failed to get changed files: %v", err)
-        os.Exit(1)
+        return 1
     }
 
     var filesToAnalyze []models.FileToAnalyze
@@ -251,53 +330,63 @@ func runDirectAnalysis(config *models.Config, toolsConfig *models.AnalysisToolsC
             EnabledTools:      []string{},
             EnableAI:          config.AI.Enabled,
             ExportFormats:     config.Export.Formats,
-            CommentThreshold:  "warning",
+            CommentThreshold:  config.Thresholds.Comments,
+            SummaryThreshold:  config.Thresholds.Summary,
+            GateThreshold:     config.Thresholds.Gate,
+            ExportThreshold:   config.Thresholds.Export,
             IncludeDependency: true,
+            OutputDir:         outDir,
         },
     }
     
    // engine = ReviewEngine(config, toolsConfig)
 
+    var stream export.Stream
+    if *streamPath != "" {
+        streamFile, err := os.Create(*streamPath)
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Failed to create stream file: %v", err)
+            return 1
+        }
+        defer streamFile.Close()
+
+        threshold, _ := models.ParseThreshold(config.Thresholds.Export)
+        stream = export.NewNDJSONStream(streamFile, threshold)
+    }
+
     engine := NewReviewEngine(config, toolsConfig)
-    result, err := engine.Analyze(ctx, request)
+    result, err := engine.AnalyzeStream(ctx, request, stream)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Analysis failed: %v", err)
-        os.Exit(1)
-    }
-
-	if err := os.MkdirAll(outDir, 0755); err != nil {
-        utils.LogWithLocation(utils.Error, "Failed to create output directory: %v", err)
-        os.Exit(1)
-    }
-
-    for _, format := range config.Export.Formats {
-        switch format {
-        case "json":
-            jsonPath := filepath.Join(outDir, fmt.Sprintf("code-review-%s.json", result.ID))
-            if err := utils.FormatToJSON(result, jsonPath); err != nil {
-                utils.LogWithLocation(utils.Error, "Failed to export to JSON: %v", err)
-                continue
-            }
-            utils.LogWithLocation(utils.Info, "JSON report saved to: %s", jsonPath)
-            
-        case "markdown":
-			mdPath := filepath.Join(outDir, fmt.Sprintf("code-review-%s.md", result.ID))
-            if _, err := utils.FormatToMarkdown(result, mdPath); err != nil {
-                utils.LogWithLocation(utils.Error, "Failed to export to Markdown: %v", err)
-                continue
-            }
-            utils.LogWithLocation(utils.Info, "Markdown report saved to: %s", mdPath)
-        }
+        return 1
     }
-    
+
     utils.LogWithLocation(utils.Info, "Analysis complete! Found %d issues", result.Summary.TotalIssues)
+
+    return gateExitCode(result.QualityGate)
+}
+
+// gateExitCode logs the quality gate verdict and returns exitGateFailed if
+// the gate failed, 0 otherwise.
+func gateExitCode(verdict *models.GateVerdict) int {
+    if verdict == nil || verdict.Status == models.GateSkipped {
+        return 0
+    }
+    for _, reason := range verdict.Reasons {
+        utils.LogWithLocation(utils.Warn, "Quality gate rule %q (%s): %s", reason.Rule, reason.Level, reason.Message)
+    }
+    utils.LogWithLocation(utils.Info, "Quality gate %s", verdict.Status)
+    if verdict.Status == models.GateFailed {
+        return exitGateFailed
+    }
+    return 0
 }
 
-func runQA(config *models.Config) {
+func runQA(config *models.Config) int {
     if *repoPath == "" {
         utils.LogWithLocation(utils.Error, "The -repo-path flag is required for Q&A mode")
         flag.Usage()
-        os.Exit(1)
+        return 1
     }
 
     ctx := context.Background()
@@ -310,7 +399,7 @@ func runQA(config *models.Config) {
     agent, err := agent.NewAgent(ctx, config, *repoPath, progressCallback)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Failed to initialize Q&A agent: %v", err)
-        os.Exit(1)
+        return 1
     }
 
     fmt.Println("✅ Agent is ready. Ask questions about your codebase. Type 'exit' to quit.")
@@ -333,9 +422,12 @@ func runQA(config *models.Config) {
         }
         fmt.Println(answer)
     }
+    return 0
 }
 
-func runAnalysisProcess(ctx context.Context, config *models.Config, toolsConfig *models.AnalysisToolsConfig, event models.WebhookEvent) {
+// runAnalysisProcess fetches, analyzes and reports on an event. It returns
+// the result, or nil if the analysis could not run.
+func runAnalysisProcess(ctx context.Context, config *models.Config, toolsConfig *models.AnalysisToolsConfig, event models.WebhookEvent) *models.AnalysisResult {
     utils.LogWithLocation(utils.Info, "Starting analysis process for event: %s", event.Type)
 
     fetcher := NewCodeFetcher()
@@ -345,7 +437,7 @@ func runAnalysisProcess(ctx context.Context, config *models.Config, toolsConfig
     repoPath, files, err := fetcher.FetchCode(ctx, event)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Failed to fetch code: %v", err)
-        return
+        return nil
     }
     defer os.RemoveAll(repoPath)
 
@@ -360,7 +452,10 @@ func runAnalysisProcess(ctx context.Context, config *models.Config, toolsConfig
             EnabledTools:      []string{},
             EnableAI:          config.AI.Enabled,
             ExportFormats:     config.Export.Formats,
-            CommentThreshold:  "warning",
+            CommentThreshold:  config.Thresholds.Comments,
+            SummaryThreshold:  config.Thresholds.Summary,
+            GateThreshold:     config.Thresholds.Gate,
+            ExportThreshold:   config.Thresholds.Export,
             IncludeDependency: false,
         },
     }
@@ -368,17 +463,18 @@ func runAnalysisProcess(ctx context.Context, config *models.Config, toolsConfig
     result, err := engine.Analyze(ctx, request)
     if err != nil {
         utils.LogWithLocation(utils.Error, "Analysis failed: %v", err)
-        return
+        return nil
     }
 
     if event.Type == "pull_request" && event.PullRequestURL != "" {
-        if err := responseHandler.SendResponse(ctx, result); err != nil {
+        if err := responseHandler.SendResponse(ctx, result, request.Settings); err != nil {
             utils.LogWithLocation(utils.Error, "Failed to send response: %v", err)
-            return
+            return result
         }
     }
     
     utils.LogWithLocation(utils.Info, "Analysis process completed successfully")
+    return result
 }
 
 func extractRepoFullNameFromURL(url string) string {
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/codeowners"
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// loadOwners reads the repository's CODEOWNERS file. It returns nil, and
+// logs why, when there is none or it can't be parsed.
+func loadOwners(repoPath string) *codeowners.Ruleset {
+    rules, err := codeowners.Load(repoPath)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "Skipping CODEOWNERS attribution: %v", err)
+        return nil
+    }
+    return rules
+}
+
+// attributeOwners sets Owners on every issue from the CODEOWNERS rules and
+// returns how many issues got owners.
+func attributeOwners(rules *codeowners.Ruleset, issues []models.CodeIssue) int {
+    if rules == nil {
+        return 0
+    }
+
+    attributed := 0
+    for i := range issues {
+        owners := rules.Owners(issues[i].File)
+        if len(owners) > 0 {
+            issues[i].Owners = owners
+            attributed++
+        }
+    }
+    return attributed
+}
+
+// criticalOwners returns the sorted owners of files with active CRITICAL findings.
+func criticalOwners(result *models.AnalysisResult) []string {
+    seen := make(map[string]bool)
+    var owners []string
+    for _, issue := range result.Issues {
+        if issue.Severity != models.Critical || issue.Status == models.StatusSuppressed {
+            continue
+        }
+        for _, owner := range issue.Owners {
+            if !seen[owner] {
+                seen[owner] = true
+                owners = append(owners, owner)
+            }
+        }
+    }
+    sort.Strings(owners)
+    return owners
+}
+
+// requestOwnerReviews asks the owners of files with CRITICAL findings to
+// review the pull request. Email owners cannot be requested and are skipped.
+func (rh *ResponseHandler) requestOwnerReviews(ctx context.Context, owner, repo string, prNumber int, result *models.AnalysisResult) error {
+    var reviewers, teams []string
+    for _, o := range criticalOwners(result) {
+        if !strings.HasPrefix(o, "@") {
+            continue
+        }
+        name := strings.TrimPrefix(o, "@")
+        if org, team, ok := strings.Cut(name, "/"); ok {
+            if strings.EqualFold(org, owner) {
+                teams = append(teams, team)
+            }
+            continue
+        }
+        reviewers = append(reviewers, name)
+    }
+    if len(reviewers) == 0 && len(teams) == 0 {
+        return nil
+    }
+
+    payload := map[string][]string{
+        "reviewers":      reviewers,
+        "team_reviewers": teams,
+    }
+    payloadBytes, err := json.Marshal(payload)
+    if err != nil {
+        return fmt.Errorf("failed to marshal review request payload: %v", err)
+    }
+
+    url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, prNumber)
+    req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+    if err != nil {
+        return fmt.Errorf("failed to create HTTP request: %v", err)
+    }
+
+    req.Header.Set("Authorization", "token "+rh.config.GitHub.APIToken)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+    client := &http.Client{Timeout: 10 * time.Second}
+    resp, err := client.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send HTTP request: %v", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 400 {
+        return fmt.Errorf("GitHub API returned error: %s", resp.Status)
+    }
+
+    utils.LogWithLocation(utils.Info, "Requested review from %d users and %d teams", len(reviewers), len(teams))
+    return nil
+}
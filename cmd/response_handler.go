@@ -12,43 +12,57 @@ import (
 	"time"
 
 	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/report"
 	"github.com/euclidstellar/gollora/internal/utils"
 )
 
 type ResponseHandler struct {
     config     *models.Config
     aggregator *ResultAggregator
+    renderer   *report.Renderer
 }
 
 func NewResponseHandler(config *models.Config) *ResponseHandler {
     return &ResponseHandler{
         config:     config,
         aggregator: NewResultAggregator(config),
+        renderer:   newRenderer(config),
     }
 }
 
-func (rh *ResponseHandler) SendResponse(ctx context.Context, result *models.AnalysisResult) error {
-    result = rh.aggregator.AggregateResults(result)
+// newRenderer loads the configured templates, falling back to the built-in
+// ones if they fail to load.
+func newRenderer(config *models.Config) *report.Renderer {
+    renderer, err := report.NewRenderer(config.Templates)
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Using built-in templates: %v", err)
+        return report.Default()
+    }
+    return renderer
+}
 
+func (rh *ResponseHandler) SendResponse(ctx context.Context, result *models.AnalysisResult, settings models.AnalysisSettings) error {
     if result.Event.Type == "pull_request" && result.Event.PullRequestURL != "" {
-        return rh.sendPullRequestComments(ctx, result)
+        return rh.sendPullRequestComments(ctx, result, settings)
     }
 
-    utils.LogWithLocation(utils.Info, "Analysis complete for push event. Results available at %s", 
-        result.OutputFiles[0].Path)
+    for _, file := range result.OutputFiles {
+        utils.LogWithLocation(utils.Info, "Analysis complete for push event. %s report available at %s",
+            file.Format, file.Path)
+    }
     
     return nil
 }
-func (rh *ResponseHandler) sendPullRequestComments(ctx context.Context, result *models.AnalysisResult) error {
+func (rh *ResponseHandler) sendPullRequestComments(ctx context.Context, result *models.AnalysisResult, settings models.AnalysisSettings) error {
     switch result.Event.Provider {
     case "github":
-        return rh.sendGitHubComments(ctx, result)
+        return rh.sendGitHubComments(ctx, result, settings)
     default:
         return fmt.Errorf("unsupported VCS provider: %s", result.Event.Provider)
     }
 }
 
-func (rh *ResponseHandler) sendGitHubComments(ctx context.Context, result *models.AnalysisResult) error {
+func (rh *ResponseHandler) sendGitHubComments(ctx context.Context, result *models.AnalysisResult, settings models.AnalysisSettings) error {
     if rh.config.GitHub.APIToken == "" {
         return fmt.Errorf("GitHub API token not configured")
     }
@@ -62,36 +76,60 @@ func (rh *ResponseHandler) sendGitHubComments(ctx context.Context, result *model
     repo := repoParts[1]
     prNumber := result.Event.PullRequestID
 
-    issuesToComment := rh.aggregator.FilterIssuesByThreshold(result, "warning")
+    issuesToComment := rh.aggregator.FilterIssuesByThreshold(result, settings.CommentThreshold)
 
     for i := range issuesToComment {
         issuesToComment[i].Event = result.Event 
     }
 
+    reviewURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber)
+    reviewEvent := rh.reviewEventForVerdict(result.QualityGate)
+
+    if rh.config.CodeOwners.Enabled && rh.config.CodeOwners.RequestReview {
+        if err := rh.requestOwnerReviews(ctx, owner, repo, prNumber, result); err != nil {
+            utils.LogWithLocation(utils.Warn, "Failed to request reviews from code owners: %v", err)
+        }
+    }
+
+    if rh.config.QualityGate.Enabled && rh.config.QualityGate.CheckRun {
+        if err := rh.postGitHubCheckRun(ctx, owner, repo, result); err != nil {
+            utils.LogWithLocation(utils.Warn, "Failed to publish quality gate check run: %v", err)
+        }
+    }
+
     if len(issuesToComment) == 0 {
         utils.LogWithLocation(utils.Info, "No issues found that meet the threshold")
-        summaryComment := "## 🎉 Code Review Results\n\nNo issues found that meet the reporting threshold. Good job!"
+        summaryComment, err := rh.formatSummaryComment(result, settings.SummaryThreshold, 0)
+        if err != nil {
+            return err
+        }
 
         summaryURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
         if err := rh.postGitHubComment(ctx, summaryURL, summaryComment); err != nil {
             utils.LogWithLocation(utils.Error, "Failed to post summary comment: %v", err)
             return err
         }
+
+        if reviewEvent != "COMMENT" {
+            if err := rh.submitGitHubReview(ctx, reviewURL, result.Event.HeadCommit, reviewEvent, rh.formatGateReviewBody(result.QualityGate), nil); err != nil {
+                utils.LogWithLocation(utils.Error, "Failed to submit %s review: %v", reviewEvent, err)
+            }
+        }
         
         return nil
     }
 
-    summaryComment := rh.formatSummaryComment(result)
-
     summaryURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
-    if err := rh.postGitHubComment(ctx, summaryURL, summaryComment); err != nil {
+    summaryComment, err := rh.formatSummaryComment(result, settings.SummaryThreshold, len(issuesToComment))
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to render summary comment: %v", err)
+    } else if err := rh.postGitHubComment(ctx, summaryURL, summaryComment); err != nil {
         utils.LogWithLocation(utils.Error, "Failed to post summary comment: %v", err)
 
     }
     
 
-    reviewURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber)
-    err := rh.postGitHubReviewComments(ctx, reviewURL, issuesToComment, result.Event.HeadCommit)
+    err = rh.postGitHubReviewComments(ctx, reviewURL, issuesToComment, result.Event.HeadCommit, reviewEvent, rh.formatGateReviewBody(result.QualityGate))
     
     if err != nil {
         // If line comments fail, try posting a consolidated comment
@@ -115,6 +153,12 @@ func (rh *ResponseHandler) sendGitHubComments(ctx context.Context, result *model
             utils.LogWithLocation(utils.Error, "Failed to post consolidated comment: %v", err)
             return err
         }
+
+        if reviewEvent != "COMMENT" {
+            if err := rh.submitGitHubReview(ctx, reviewURL, result.Event.HeadCommit, reviewEvent, rh.formatGateReviewBody(result.QualityGate), nil); err != nil {
+                utils.LogWithLocation(utils.Error, "Failed to submit %s review: %v", reviewEvent, err)
+            }
+        }
     }
     
     utils.LogWithLocation(utils.Info, "Successfully sent %d comments to GitHub PR #%d", len(issuesToComment)+1, prNumber)
@@ -153,7 +197,7 @@ func (rh *ResponseHandler) postGitHubComment(ctx context.Context, url, body stri
     return nil
 }
 
-func (rh *ResponseHandler) postGitHubReviewComments(ctx context.Context, url string, issues []models.CodeIssue, commitSHA string) error {
+func (rh *ResponseHandler) postGitHubReviewComments(ctx context.Context, url string, issues []models.CodeIssue, commitSHA, event, body string) error {
    
     if len(issues) == 0 {
         return nil
@@ -235,11 +279,16 @@ func (rh *ResponseHandler) postGitHubReviewComments(ctx context.Context, url str
     
     for filePath, fileIssues := range commentsByFile {
         for _, issue := range fileIssues {
+            body, err := rh.renderer.Issue(issue)
+            if err != nil {
+                return err
+            }
+
             // Create comment
             comment := map[string]interface{}{
                 "path": filePath,
                 "line": issue.Line,
-                "body": rh.formatIssueComment(issue),
+                "body": body,
                 "side": "RIGHT",  
             }
             comments = append(comments, comment)
@@ -252,11 +301,22 @@ func (rh *ResponseHandler) postGitHubReviewComments(ctx context.Context, url str
     
     commentBytes, _ := json.Marshal(comments)
     utils.LogWithLocation(utils.Debug, "Sending review comments: %s", string(commentBytes))
-    
+
+    return rh.submitGitHubReview(ctx, url, commitSHA, event, body, comments)
+}
+
+// submitGitHubReview creates a pull request review with the given event
+// (COMMENT, APPROVE or REQUEST_CHANGES) and optional line comments.
+func (rh *ResponseHandler) submitGitHubReview(ctx context.Context, url, commitSHA, event, body string, comments []map[string]interface{}) error {
     payload := map[string]interface{}{
         "commit_id": commitSHA,
-        "event":     "COMMENT",
-        "comments":  comments,
+        "event":     event,
+    }
+    if body != "" {
+        payload["body"] = body
+    }
+    if len(comments) > 0 {
+        payload["comments"] = comments
     }
     
     payloadBytes, err := json.Marshal(payload)
@@ -288,74 +348,21 @@ func (rh *ResponseHandler) postGitHubReviewComments(ctx context.Context, url str
     return nil
 }
 
-func (rh *ResponseHandler) formatSummaryComment(result *models.AnalysisResult) string {
-    var sb strings.Builder
-    
-    sb.WriteString("# :robot: Gollora Code Review\n\n")
-    sb.WriteString("## Summary\n\n")
-    totalissues :=  result.Summary.CriticalCount + result.Summary.ErrorCount + 
-    result.Summary.WarningCount + result.Summary.InfoCount
-    sb.WriteString("| Metric | Value |\n")
-    sb.WriteString("|--------|-------|\n")
-    sb.WriteString(fmt.Sprintf("| Total Issues | %d |\n", totalissues))
-    sb.WriteString(fmt.Sprintf("| Critical | %d |\n", result.Summary.CriticalCount))
-    sb.WriteString(fmt.Sprintf("| Errors | %d |\n", result.Summary.ErrorCount))
-    sb.WriteString(fmt.Sprintf("| Warnings | %d |\n", result.Summary.WarningCount))
-    sb.WriteString(fmt.Sprintf("| Infos | %d |\n", result.Summary.InfoCount))
-   // sb.WriteString(fmt.Sprintf("| Files Analyzed | %d |\n", result.Summary.FileCount))
-    
-    // if len(result.OutputFiles) > 0 {
-    //     sb.WriteString("\n## Detailed Reports\n\n")
-        
-    //     for _, file := range result.OutputFiles {
-    //         sb.WriteString(fmt.Sprintf("- [%s Report](%s)\n", strings.Title(file.Format), file.URL))
-    //     }
-    // }
-    
-    // if result.Summary.TotalIssues > 0 {
-    //     sb.WriteString("\n## Issue Breakdown\n\n")
-        
-    //     sb.WriteString("### By Type\n\n")
-    //     sb.WriteString("| Type | Count |\n")
-    //     sb.WriteString("|------|-------|\n")
-    //     for typ, count := range result.Summary.IssuesByType {
-    //         sb.WriteString(fmt.Sprintf("| %s | %d |\n", rh.formatIssueType(typ), count))
-    //     }
-    
-    //     if len(result.Summary.IssuesByFile) > 0 {
-    //         sb.WriteString("\n### By File (Top 5)\n\n")
-    //         sb.WriteString("| File | Count |\n")
-    //         sb.WriteString("|------|-------|\n")
-            
-    //         type fileCount struct {
-    //             file  string
-    //             count int
-    //         }
-    //         var files []fileCount
-    //         for file, count := range result.Summary.IssuesByFile {
-    //             files = append(files, fileCount{file, count})
-    //         }
-            
-    //         sort.Slice(files, func(i, j int) bool {
-    //             return files[i].count > files[j].count
-    //         })
-            
-    //         // Show top 5
-    //         limit := 5
-    //         if len(files) < limit {
-    //             limit = len(files)
-    //         }
-            
-    //         for i := 0; i < limit; i++ {
-    //             sb.WriteString(fmt.Sprintf("| `%s` | %d |\n", files[i].file, files[i].count))
-    //         }
-    //     }
-    // }
-    
-    sb.WriteString("\n\n---\n")
-    sb.WriteString("This review was generated automatically by [Gollora](https://github.com/euclidstellar/gollora) :sparkles:")
-    
-    return sb.String()
+// formatSummaryComment renders the PR summary. Only severities allowed by
+// threshold get a row, and the total is the sum of those rows. commented is
+// the number of inline comments posted; with none the template renders the
+// short all-clear comment.
+func (rh *ResponseHandler) formatSummaryComment(result *models.AnalysisResult, threshold string, commented int) (string, error) {
+    t, _ := models.ParseThreshold(threshold)
+    data := report.SummaryData{
+        Result:    result,
+        Threshold: t,
+        Commented: commented,
+    }
+    if rh.config.CodeOwners.MentionCritical {
+        data.MentionOwners = criticalOwners(result)
+    }
+    return rh.renderer.Summary(data)
 }
 
 func (rh *ResponseHandler) parseGitHubPRDiff(ctx context.Context, owner, repo string, prNumber int) (map[string]map[int]bool, error) {
@@ -425,68 +432,111 @@ func (rh *ResponseHandler) parseGitHubPRDiff(ctx context.Context, owner, repo st
     return validPaths, nil
 }
 
-func (rh *ResponseHandler) formatIssueComment(issue models.CodeIssue) string {
-    var sb strings.Builder
-    
-    emoji := rh.getEmojiForSeverity(issue.Severity)
-    
-    sb.WriteString(fmt.Sprintf("%s **%s: %s**\n\n", emoji, issue.Severity, issue.Title))
-    sb.WriteString(fmt.Sprintf("%s\n\n", issue.Description))
-    
-    if issue.Fix != "" {
-        sb.WriteString("**Suggested Fix:**\n\n")
-        sb.WriteString("```\n")
-        sb.WriteString(issue.Fix)
-        sb.WriteString("\n```\n\n")
-    }
-    
-    sb.WriteString(fmt.Sprintf("*Detected by %s*", issue.Tool))
-    
-    if issue.URL != "" {
-        sb.WriteString(fmt.Sprintf(" • [More info](%s)", issue.URL))
+// reviewEventForVerdict maps the quality gate verdict to a GitHub review
+// event. APPROVE is opt-in since the reviewing token may belong to a user.
+func (rh *ResponseHandler) reviewEventForVerdict(verdict *models.GateVerdict) string {
+    if verdict == nil {
+        return "COMMENT"
+    }
+    switch verdict.Status {
+    case models.GateFailed:
+        return "REQUEST_CHANGES"
+    case models.GatePassed:
+        if rh.config.QualityGate.Approve {
+            return "APPROVE"
+        }
     }
-    
-    return sb.String()
+    return "COMMENT"
 }
 
-func (rh *ResponseHandler) getEmojiForSeverity(severity models.IssueSeverity) string {
-    switch severity {
-    case models.Critical:
-        return ":rotating_light:"
-    case models.Error:
-        return ":x:"
-    case models.Warning:
-        return ":warning:"
-    case models.Info:
-        return ":information_source:"
-    case models.Hint:
-        return ":bulb:"
+// checkRunConclusion maps the quality gate verdict to a Check Run conclusion.
+func checkRunConclusion(verdict *models.GateVerdict) string {
+    if verdict == nil {
+        return "neutral"
+    }
+    switch verdict.Status {
+    case models.GatePassed:
+        return "success"
+    case models.GateFailed:
+        return "failure"
     default:
-        return ":mag:"
+        return "neutral"
     }
 }
 
-func (rh *ResponseHandler) formatIssueType(typ string) string {
-    switch typ {
-    case string(models.Security):
-        return "Security"
-    case string(models.Performance):
-        return "Performance"
-    case string(models.Bug):
-        return "Bug"
-    case string(models.Maintainability):
-        return "Maintainability"
-    case string(models.Dependency):
-        return "Dependency"
-    case string(models.CodeStyle):
-        return "Code Style"
-    case string(models.Test):
-        return "Test"
-    case string(models.Documentation):
-        return "Documentation"
-    case string(models.AIInsight):
-        return "AI Insight"
-    default:
-        return typ
+// formatGateSection renders the "gate" template section, empty when the
+// gate was skipped.
+func (rh *ResponseHandler) formatGateSection(verdict *models.GateVerdict) string {
+    section, err := rh.renderer.Section("gate", verdict)
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to render quality gate section: %v", err)
+        return ""
+    }
+    return section
+}
+
+func (rh *ResponseHandler) formatGateReviewBody(verdict *models.GateVerdict) string {
+    section := strings.TrimSpace(rh.formatGateSection(verdict))
+    if section == "" {
+        return ""
     }
-}
\ No newline at end of file
+    return "# :robot: Gollora Code Review\n\n" + section
+}
+
+// postGitHubCheckRun publishes the quality gate verdict as a completed Check
+// Run on the head commit. GitHub only accepts this from GitHub App tokens.
+func (rh *ResponseHandler) postGitHubCheckRun(ctx context.Context, owner, repo string, result *models.AnalysisResult) error {
+    title := "Quality gate passed"
+    if result.QualityGate != nil {
+        switch result.QualityGate.Status {
+        case models.GateFailed:
+            title = "Quality gate failed"
+        case models.GateWarned:
+            title = "Quality gate passed with warnings"
+        }
+    }
+
+    summary := fmt.Sprintf("%d issues (%d critical, %d error, %d warning)",
+        result.Summary.TotalIssues, result.Summary.CriticalCount, result.Summary.ErrorCount, result.Summary.WarningCount)
+    summary += rh.formatGateSection(result.QualityGate)
+
+    payload := map[string]interface{}{
+        "name":       "Gollora Quality Gate",
+        "head_sha":   result.Event.HeadCommit,
+        "status":     "completed",
+        "conclusion": checkRunConclusion(result.QualityGate),
+        "output": map[string]string{
+            "title":   title,
+            "summary": summary,
+        },
+    }
+
+    payloadBytes, err := json.Marshal(payload)
+    if err != nil {
+        return fmt.Errorf("failed to marshal check run payload: %v", err)
+    }
+
+    url := fmt.Sprintf("https://api.github.com/repos/%s/%s/check-runs", owner, repo)
+    req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+    if err != nil {
+        return fmt.Errorf("failed to create HTTP request: %v", err)
+    }
+
+    req.Header.Set("Authorization", "token "+rh.config.GitHub.APIToken)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Accept", "application/vnd.github+json")
+
+    client := &http.Client{Timeout: 10 * time.Second}
+    resp, err := client.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send HTTP request: %v", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 400 {
+        bodyBytes, _ := io.ReadAll(resp.Body)
+        return fmt.Errorf("GitHub API returned error: %s, body: %s", resp.Status, string(bodyBytes))
+    }
+
+    return nil
+}
@@ -1,33 +1,54 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 
 	"github.com/euclidstellar/gollora/internal/analyzers"
+	"github.com/euclidstellar/gollora/internal/codeowners"
+	"github.com/euclidstellar/gollora/internal/depgraph"
+	"github.com/euclidstellar/gollora/internal/export"
+	"github.com/euclidstellar/gollora/internal/history"
 	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/policy"
+	"github.com/euclidstellar/gollora/internal/report"
 	"github.com/euclidstellar/gollora/internal/utils"
-	"golang.org/x/mod/modfile"
 )
 
 type ReviewEngine struct {
-    config      *models.Config
-    toolsConfig *models.AnalysisToolsConfig
+    config         *models.Config
+    toolsConfig    *models.AnalysisToolsConfig
+    severityPolicy *policy.SeverityPolicy
+    aggregator     *ResultAggregator
+    renderer       *report.Renderer
 }
 
 func NewReviewEngine(config *models.Config, toolsConfig *models.AnalysisToolsConfig) *ReviewEngine {
+    severityPolicy, err := policy.NewSeverityPolicy(toolsConfig.SeverityPolicy)
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Ignoring invalid severity policy: %v", err)
+    }
+
     return &ReviewEngine{
-        config:      config,
-        toolsConfig: toolsConfig,
+        config:         config,
+        toolsConfig:    toolsConfig,
+        severityPolicy: severityPolicy,
+        aggregator:     NewResultAggregator(config),
+        renderer:       newRenderer(config),
     }
 }
 
 func (re *ReviewEngine) Analyze(ctx context.Context, request models.AnalysisRequest) (*models.AnalysisResult, error) {
+    return re.AnalyzeStream(ctx, request, nil)
+}
+
+// AnalyzeStream runs the analysis like Analyze, passing each finding of the
+// aggregated result to stream before the reports are exported, and the
+// finished result at the end. A stream that fails is dropped; the analysis
+// carries on without it.
+func (re *ReviewEngine) AnalyzeStream(ctx context.Context, request models.AnalysisRequest, stream export.Stream) (*models.AnalysisResult, error) {
     utils.LogWithLocation(utils.Info, "Starting code analysis for %s", request.Event.RepoFullName)
     result := models.NewAnalysisResult(request.Event)
 
@@ -39,6 +60,27 @@ func (re *ReviewEngine) Analyze(ctx context.Context, request models.AnalysisRequ
     var wg sync.WaitGroup
     var resultMutex sync.Mutex // Mutex to protect result from concurrent writes
 
+    var owners *codeowners.Ruleset
+    if re.config.CodeOwners.Enabled {
+        owners = loadOwners(request.RepoPath)
+    }
+
+    // addIssues applies the per-issue stages to a batch as it arrives.
+    fingerprints := models.NewFingerprinter(request.RepoPath)
+    remapped, suppressed, owned := 0, 0, 0
+    addIssues := func(issues []models.CodeIssue) {
+        resultMutex.Lock()
+        fingerprints.Assign(issues)
+        remapped += re.severityPolicy.Apply(issues)
+        suppressed += analyzers.ApplySuppressions(issues, request.Files)
+        owned += attributeOwners(owners, issues)
+
+        for _, issue := range issues {
+            result.AddIssue(issue)
+        }
+        resultMutex.Unlock()
+    }
+
     for lang, files := range filesByLang {
         if !re.isLanguageEnabled(lang, request.Settings.EnabledLanguages) {
             continue
@@ -66,6 +108,27 @@ func (re *ReviewEngine) Analyze(ctx context.Context, request models.AnalysisRequ
             case "python":
                 analyzer := analyzers.NewPythonAnalyzer(tools.Tools)
                 issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "java":
+                analyzer := analyzers.NewJavaAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "javascript", "typescript":
+                analyzer := analyzers.NewJavaScriptAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "shell":
+                analyzer := analyzers.NewShellAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "dockerfile":
+                analyzer := analyzers.NewDockerfileAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "yaml":
+                analyzer := analyzers.NewWorkflowAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "rust":
+                analyzer := analyzers.NewRustAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
+            case "c", "cpp":
+                analyzer := analyzers.NewCppAnalyzer(tools.Tools)
+                issues, err = analyzer.Analyze(ctx, request.RepoPath, languageFiles)
             default:
                 utils.LogWithLocation(utils.Info, "No analyzer available for language: %s", language)
                 return
@@ -76,11 +139,7 @@ func (re *ReviewEngine) Analyze(ctx context.Context, request models.AnalysisRequ
                 return
             }
 
-            resultMutex.Lock()
-            for _, issue := range issues {
-                result.AddIssue(issue)
-            }
-            resultMutex.Unlock()
+            addIssues(issues)
             
         }(lang, files)
     }
@@ -105,34 +164,148 @@ func (re *ReviewEngine) Analyze(ctx context.Context, request models.AnalysisRequ
                 return
             }
 
-            resultMutex.Lock()
-            for _, issue := range issues {
-                result.AddIssue(issue)
+            addIssues(issues)
+        }()
+    }
+
+    // SARIF reports cover any language, so they are imported over all files.
+    if sarifTools, ok := re.toolsConfig.Languages["sarif"]; ok && sarifTools.Enabled {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+
+            utils.LogWithLocation(utils.Info, "Importing SARIF reports")
+            analyzer := analyzers.NewSARIFAnalyzer(sarifTools.Tools)
+            issues, err := analyzer.Analyze(ctx, request.RepoPath, request.Files)
+            if err != nil {
+                utils.LogWithLocation(utils.Error, "Error importing SARIF reports: %v", err)
+                return
             }
-            resultMutex.Unlock()
+
+            addIssues(issues)
         }()
     }
 
+    // Dependency analysis reads the manifests rather than the changed files.
+    var dependencies *models.DependencyGraph
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+
+        var issues []models.CodeIssue
+        dependencies, issues = analyzeDependencies(ctx, request)
+        addIssues(issues)
+    }()
+
     wg.Wait()
 
-    // Perform dependency analysis
-    analyzeDependencies(request.RepoPath, result)
+    if remapped > 0 {
+        utils.LogWithLocation(utils.Info, "Severity policy reclassified %d issues", remapped)
+    }
+    if suppressed > 0 {
+        utils.LogWithLocation(utils.Info, "Suppressed %d issues via gollora:ignore directives", suppressed)
+    }
+    if owners != nil {
+        utils.LogWithLocation(utils.Info, "Attributed %d issues to owners from %s", owned, owners.Path)
+    }
+
+    // Aggregate before anything is recorded, scored or exported so the
+    // history, gate, reports and PR comment all see the same issues.
+    result = re.aggregator.AggregateResults(ctx, result)
+
+    if re.config.History.Enabled {
+        result.History = re.recordHistory(request, result)
+    }
+
+    // Issues are final once history has set their lifecycle, so they are
+    // streamed from here: consumers never see a duplicate that aggregation
+    // merges or a severity that AI scoring changes.
+    stream = streamIssues(stream, result.Issues)
+
+    if !dependencies.Empty() {
+        result.Dependencies = dependencies
+        result.Summary.DependencyGraph = depgraph.Mermaid(dependencies)
+    }
 
     result.CompleteAnalysis()
+    result.Risk = assessRisk(re.config, request, result)
+    gateThreshold, _ := models.ParseThreshold(request.Settings.GateThreshold)
+    result.QualityGate = policy.EvaluateGate(re.config.QualityGate, result, gateThreshold)
 
-    outputDir := filepath.Join(request.RepoPath, "code-review-output")
-    if err := os.MkdirAll(outputDir, 0755); err != nil {
-        utils.LogWithLocation(utils.Error, "Failed to create output directory: %v", err)
+    exportThreshold, _ := models.ParseThreshold(request.Settings.ExportThreshold)
+    if exportThreshold.Disabled {
+        utils.LogWithLocation(utils.Info, "Export threshold is none, skipping report export")
+    } else if sink, err := export.NewSink(re.config.Export, request.Settings.OutputDir); err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to create export sink: %v", err)
     } else {
-        re.exportResults(result, outputDir, request.Settings.ExportFormats)
+        exporter := export.NewExporter(sink, export.Options{RepoPath: request.RepoPath, Renderer: re.renderer})
+        result.OutputFiles = exporter.Export(ctx, result.FilterBySeverity(exportThreshold), request.Settings.ExportFormats)
     }
     
+    if stream != nil {
+        if err := stream.Finish(result); err != nil {
+            utils.LogWithLocation(utils.Warn, "Failed to finish findings stream: %v", err)
+        }
+    }
+
     utils.LogWithLocation(utils.Info, "Analysis complete. Found %d issues (%d critical, %d error, %d warning)",
         result.Summary.TotalIssues, result.Summary.CriticalCount, result.Summary.ErrorCount, result.Summary.WarningCount)
     
     return result, nil
 }
 
+// streamIssues passes issues to stream, returning nil if the stream fails.
+func streamIssues(stream export.Stream, issues []models.CodeIssue) export.Stream {
+    if stream == nil {
+        return nil
+    }
+    for _, issue := range issues {
+        if err := stream.Issue(issue); err != nil {
+            utils.LogWithLocation(utils.Warn, "Stopped streaming findings: %v", err)
+            return nil
+        }
+    }
+    return stream
+}
+
+// recordHistory stores the analysis in the issue history and sets each
+// issue's lifecycle status. Failures are logged and leave the result as is.
+func (re *ReviewEngine) recordHistory(request models.AnalysisRequest, result *models.AnalysisResult) *models.LifecycleSummary {
+    store, err := history.Open(re.config.History.Path, re.config.History.MaxAnalyses)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "Issue history unavailable: %v", err)
+        return nil
+    }
+
+    repo := request.Event.RepoFullName
+    if repo == "" {
+        repo = request.Event.RepoURL
+    }
+    var files []string
+    for _, file := range request.Files {
+        files = append(files, file.Path)
+    }
+
+    summary, err := store.Record(history.Run{
+        Repo:          repo,
+        Branch:        request.Event.Branch,
+        BaseBranch:    request.Event.BaseBranch,
+        Commit:        request.Event.HeadCommit,
+        AnalysisID:    result.ID,
+        PullRequestID: request.Event.PullRequestID,
+        Files:         files,
+        At:            result.AnalyzedAt,
+    }, result.Issues)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "Failed to record issue history: %v", err)
+        return nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Issue history: %d new, %d reintroduced, %d persisting, %d fixed",
+        summary.New, summary.Reintroduced, summary.Persisting, summary.Fixed)
+    return summary
+}
+
 func (re *ReviewEngine) isLanguageEnabled(language string, enabledLanguages []string) bool {
     if len(enabledLanguages) == 0 {
         if langConfig, ok := re.toolsConfig.Languages[language]; ok {
@@ -198,144 +371,59 @@ func isHighPriorityFile(path string) bool {
     return false
 }
 
-// analyzeDependencies parses dependency files and generates a visualization graph.
-func analyzeDependencies(repoPath string, result *models.AnalysisResult) {
+// analyzeDependencies builds the dependency graph from the repository's
+// manifests. Go module problems are reported as issues when dependencies
+// were asked for or the change touches go.mod or go.sum.
+func analyzeDependencies(ctx context.Context, request models.AnalysisRequest) (*models.DependencyGraph, []models.CodeIssue) {
     utils.LogWithLocation(utils.Info, "Starting dependency analysis.")
-    var graphBuilder strings.Builder
-    graphBuilder.WriteString("graph TD\n")
-    foundDependencies := false
+    repoPath := request.RepoPath
+    graph := &models.DependencyGraph{}
+    var issues []models.CodeIssue
 
     // --- Go Dependency Analysis ---
-    goModPath := filepath.Join(repoPath, "go.mod")
-    if _, err := os.Stat(goModPath); err == nil {
+    if _, err := os.Stat(filepath.Join(repoPath, "go.mod")); err == nil {
         utils.LogWithLocation(utils.Info, "Found go.mod, analyzing Go dependencies.")
-        goDeps, err := parseGoMod(goModPath)
+        mods, err := depgraph.AddGoModules(ctx, graph, repoPath)
         if err != nil {
             utils.LogWithLocation(utils.Warn, "Failed to parse go.mod: %v", err)
         } else {
-            projectName := "Project(Go)"
-            graphBuilder.WriteString(fmt.Sprintf("    subgraph %s\n", projectName))
-            for _, dep := range goDeps {
-                graphBuilder.WriteString(fmt.Sprintf("        %s --> \"%s\";\n", projectName, dep))
+            if mods.GraphError != nil {
+                utils.LogWithLocation(utils.Warn, "Falling back to the module cache for the Go module graph: %v", mods.GraphError)
             }
-            graphBuilder.WriteString("    end\n")
-            foundDependencies = true
-        }
-    }
-
-    // --- Python Dependency Analysis ---
-    reqPath := filepath.Join(repoPath, "requirements.txt")
-    if _, err := os.Stat(reqPath); err == nil {
-        utils.LogWithLocation(utils.Info, "Found requirements.txt, analyzing Python dependencies.")
-        pyDeps, err := parseRequirementsTxt(reqPath)
-        if err != nil {
-            utils.LogWithLocation(utils.Warn, "Failed to parse requirements.txt: %v", err)
-        } else {
-            projectName := "Project(Python)"
-            graphBuilder.WriteString(fmt.Sprintf("    subgraph %s\n", projectName))
-            for _, dep := range pyDeps {
-                graphBuilder.WriteString(fmt.Sprintf("        %s --> \"%s\";\n", projectName, dep))
+            utils.LogWithLocation(utils.Info, "Resolved %d Go modules from %s.", len(mods.BuildList), mods.Source)
+            if request.Settings.IncludeDependency || touchesGoModules(request.Files) {
+                issues = mods.Check()
             }
-            graphBuilder.WriteString("    end\n")
-            foundDependencies = true
         }
     }
 
-    if foundDependencies {
-        result.Summary.DependencyGraph = graphBuilder.String()
-        utils.LogWithLocation(utils.Info, "Dependency analysis complete.")
-    } else {
-        utils.LogWithLocation(utils.Info, "No supported dependency files found (go.mod, requirements.txt).")
-    }
-}
-
-// parseGoMod reads a go.mod file and extracts direct dependencies.
-func parseGoMod(path string) ([]string, error) {
-    content, err := os.ReadFile(path)
-    if err != nil {
-        return nil, err
-    }
-    modFile, err := modfile.Parse(path, content, nil)
-    if err != nil {
-        return nil, err
+    // --- Python Dependency Analysis ---
+    project := request.Event.RepoFullName
+    if project == "" {
+        project = filepath.Base(repoPath)
     }
-
-    var deps []string
-    for _, require := range modFile.Require {
-        if !require.Indirect {
-            deps = append(deps, require.Mod.Path)
+    for _, reqFile := range depgraph.RequirementFiles {
+        reqPath := filepath.Join(repoPath, reqFile.Name)
+        if _, err := os.Stat(reqPath); err != nil {
+            continue
         }
-    }
-    return deps, nil
-}
-
-// parseRequirementsTxt reads a requirements.txt file and extracts dependencies.
-func parseRequirementsTxt(path string) ([]string, error) {
-    file, err := os.Open(path)
-    if err != nil {
-        return nil, err
-    }
-    defer file.Close()
-
-    var deps []string
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        line := strings.TrimSpace(scanner.Text())
-        if line != "" && !strings.HasPrefix(line, "#") {
-            // Simple parsing, ignores version specifiers for clarity in the graph
-            dep := strings.Split(line, "==")[0]
-            dep = strings.Split(dep, ">=")[0]
-            dep = strings.Split(dep, "<=")[0]
-            deps = append(deps, strings.TrimSpace(dep))
+        utils.LogWithLocation(utils.Info, "Found %s, analyzing Python dependencies.", reqFile.Name)
+        if err := depgraph.AddRequirements(graph, reqPath, project, reqFile.Scope); err != nil {
+            utils.LogWithLocation(utils.Warn, "Failed to parse %s: %v", reqFile.Name, err)
         }
     }
-    return deps, scanner.Err()
-}
 
-func (re *ReviewEngine) exportResults(result *models.AnalysisResult, outputDir string, formats []string) {
-    for _, format := range formats {
-        switch format {
-        case "json":
-            jsonPath := filepath.Join(outputDir, fmt.Sprintf("code-review-%s.json", result.ID))
-            if err := utils.FormatToJSON(result, jsonPath); err != nil {
-                utils.LogWithLocation(utils.Error, "Failed to export results to JSON: %v", err)
-                continue
-            }
-            result.OutputFiles = append(result.OutputFiles, models.OutputFile{
-                Format: "json",
-                Path:   jsonPath,
-            })
-            
-        case "markdown":
-            mdPath := filepath.Join(outputDir, fmt.Sprintf("code-review-%s.md", result.ID))
-            md, err := utils.FormatToMarkdown(result, mdPath)
-            if err != nil {
-                utils.LogWithLocation(utils.Error, "Failed to export results to Markdown: %v", err)
-                continue
-            }
-            result.OutputFiles = append(result.OutputFiles, models.OutputFile{
-                Format: "markdown",
-                Path:   mdPath,
-            })
-           
-            if containsFormat(formats, "pdf") {
-                pdfPath := filepath.Join(outputDir, fmt.Sprintf("code-review-%s.pdf", result.ID))
-                if err := utils.FormatToPDF(md, pdfPath); err != nil {
-                    utils.LogWithLocation(utils.Error, "Failed to export results to PDF: %v", err)
-                    continue
-                }
-                result.OutputFiles = append(result.OutputFiles, models.OutputFile{
-                    Format: "pdf",
-                    Path:   pdfPath,
-                })
-            }
-        }
+    if graph.Empty() {
+        utils.LogWithLocation(utils.Info, "No supported dependency files found (go.mod, requirements.txt).")
+        return nil, issues
     }
+    utils.LogWithLocation(utils.Info, "Dependency analysis complete: %d packages, %d edges, %d issues.", len(graph.Nodes), len(graph.Edges), len(issues))
+    return graph, issues
 }
 
-func containsFormat(formats []string, format string) bool {
-    for _, f := range formats {
-        if f == format {
+func touchesGoModules(files []models.FileToAnalyze) bool {
+    for _, file := range files {
+        if file.Path == "go.mod" || file.Path == "go.sum" {
             return true
         }
     }
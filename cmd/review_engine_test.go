@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/export"
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+func TestMain(m *testing.M) {
+    utils.InitLogger(io.Discard, io.Discard, io.Discard, io.Discard)
+    os.Exit(m.Run())
+}
+
+// sarifReport is a one-run SARIF log reporting rule at app.py:line.
+func sarifReport(tool, rule string, line int) string {
+    return fmt.Sprintf(`{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": %q}}, "results": [
+        {"ruleId": %q, "level": "warning", "message": {"text": "finding"},
+         "locations": [{"physicalLocation": {"artifactLocation": {"uri": "app.py"}, "region": {"startLine": %d}}}]}
+    ]}]}`, tool, rule, line)
+}
+
+// newStreamTestRequest sets up a repository whose flake8 and ruff reports
+// flag the same unused import, plus one finding only ruff reports.
+func newStreamTestRequest(t *testing.T) (*ReviewEngine, models.AnalysisRequest) {
+    t.Helper()
+    dir := t.TempDir()
+    files := map[string]string{
+        "app.py":       "import os\nimport sys\n\nx = 1\n",
+        "flake8.sarif": sarifReport("flake8", "F401", 1),
+        "ruff.sarif":   sarifReport("ruff", "F401", 1),
+        "extra.sarif":  sarifReport("ruff", "E501", 4),
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+            t.Fatalf("failed to write %s: %v", name, err)
+        }
+    }
+
+    toolsConfig := &models.AnalysisToolsConfig{
+        Languages: map[string]models.LanguageConfig{
+            "sarif": {Enabled: true, Tools: []models.Tool{
+                {Name: "flake8", Args: []string{"flake8.sarif"}, Enabled: true},
+                {Name: "ruff", Args: []string{"ruff.sarif", "extra.sarif"}, Enabled: true},
+            }},
+        },
+    }
+    request := models.AnalysisRequest{
+        Event:    models.WebhookEvent{RepoFullName: "example/app"},
+        RepoPath: dir,
+        Files:    []models.FileToAnalyze{{Path: "app.py", Language: "python"}},
+        Settings: models.AnalysisSettings{ExportThreshold: "none"},
+    }
+    return NewReviewEngine(&models.Config{}, toolsConfig), request
+}
+
+func TestAnalyzeStreamMatchesSummary(t *testing.T) {
+    engine, request := newStreamTestRequest(t)
+
+    var out bytes.Buffer
+    result, err := engine.AnalyzeStream(context.Background(), request, export.NewNDJSONStream(&out, models.Threshold{}))
+    if err != nil {
+        t.Fatalf("AnalyzeStream failed: %v", err)
+    }
+
+    var streamed []models.CodeIssue
+    var summary *export.SummaryRecord
+    dec := json.NewDecoder(&out)
+    for dec.More() {
+        var raw map[string]interface{}
+        if err := dec.Decode(&raw); err != nil {
+            t.Fatalf("invalid NDJSON line: %v", err)
+        }
+        data, _ := json.Marshal(raw)
+        if raw["record"] == "summary" {
+            summary = &export.SummaryRecord{}
+            json.Unmarshal(data, summary)
+            continue
+        }
+        if summary != nil {
+            t.Fatal("issue streamed after the summary")
+        }
+        var issue models.CodeIssue
+        json.Unmarshal(data, &issue)
+        streamed = append(streamed, issue)
+    }
+
+    if summary == nil {
+        t.Fatal("no summary record")
+    }
+    if len(streamed) != 2 {
+        t.Fatalf("streamed %d issues, want the 2 left after merging: %+v", len(streamed), streamed)
+    }
+    if summary.Summary.TotalIssues != len(streamed) {
+        t.Errorf("summary counts %d issues, %d streamed", summary.Summary.TotalIssues, len(streamed))
+    }
+    for i, issue := range streamed {
+        want := result.Issues[i]
+        if issue.Fingerprint != want.Fingerprint || issue.Severity != want.Severity {
+            t.Errorf("streamed issue %d = %s/%s, result has %s/%s",
+                i, issue.Fingerprint, issue.Severity, want.Fingerprint, want.Severity)
+        }
+    }
+    if streamed[0].Metadata["merged_tools"] != "flake8,ruff" {
+        t.Errorf("merged_tools = %q, want the merged F401 finding", streamed[0].Metadata["merged_tools"])
+    }
+}
+
+// failingStream fails on the first issue.
+type failingStream struct {
+    issues, finished int
+}
+
+func (s *failingStream) Issue(issue models.CodeIssue) error {
+    s.issues++
+    return fmt.Errorf("client went away")
+}
+
+func (s *failingStream) Finish(result *models.AnalysisResult) error {
+    s.finished++
+    return nil
+}
+
+func TestAnalyzeStreamDropsFailedStream(t *testing.T) {
+    engine, request := newStreamTestRequest(t)
+
+    stream := &failingStream{}
+    result, err := engine.AnalyzeStream(context.Background(), request, stream)
+    if err != nil {
+        t.Fatalf("AnalyzeStream failed: %v", err)
+    }
+    if stream.issues != 1 || stream.finished != 0 {
+        t.Errorf("stream got %d issues and %d finishes, want 1 and 0", stream.issues, stream.finished)
+    }
+    if result.Summary.TotalIssues != 2 {
+        t.Errorf("result has %d issues, want 2", result.Summary.TotalIssues)
+    }
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+const (
+    riskDiffSize  = "diff_size"
+    riskHotspots  = "hotspots"
+    riskOwnership = "ownership"
+    riskTests     = "test_changes"
+    riskFindings  = "findings"
+
+    defaultRiskHistoryMonths = 6
+)
+
+var defaultRiskWeights = map[string]float64{
+    riskDiffSize:  0.20,
+    riskHotspots:  0.20,
+    riskOwnership: 0.15,
+    riskTests:     0.15,
+    riskFindings:  0.30,
+}
+
+// riskSourceLanguages are the languages whose changes are expected to come
+// with test changes.
+var riskSourceLanguages = map[string]bool{
+    "go": true, "python": true, "java": true, "javascript": true, "typescript": true,
+    "rust": true, "c": true, "cpp": true, "ruby": true, "php": true,
+}
+
+// assessRisk computes the risk score for a change from its diff size, the
+// git history of the changed files, test co-changes and the findings.
+func assessRisk(config *models.Config, request models.AnalysisRequest, result *models.AnalysisResult) *models.RiskScore {
+    months := config.Risk.HistoryMonths
+    if months <= 0 {
+        months = defaultRiskHistoryMonths
+    }
+
+    var paths []string
+    for _, file := range request.Files {
+        paths = append(paths, file.Path)
+    }
+
+    history, err := utils.GetFileHistory(request.RepoPath, time.Now().AddDate(0, -months, 0), paths)
+    if err != nil {
+        utils.LogWithLocation(utils.Warn, "Risk score will ignore file history: %v", err)
+    }
+
+    risk := &models.RiskScore{}
+    risk.SetFactor(diffSizeRisk(request))
+    risk.SetFactor(hotspotRisk(history, paths, months))
+    risk.SetFactor(ownershipRisk(history, paths))
+    risk.SetFactor(testChangeRisk(paths))
+    risk.SetFactor(NewResultAggregator(config).findingsRisk(result))
+
+    applyRiskWeights(config, risk)
+    risk.Recompute()
+    return risk
+}
+
+func applyRiskWeights(config *models.Config, risk *models.RiskScore) {
+    for i := range risk.Factors {
+        f := &risk.Factors[i]
+        f.Weight = defaultRiskWeights[f.Name]
+        if w, ok := config.Risk.Weights[f.Name]; ok {
+            f.Weight = w
+        }
+    }
+}
+
+// saturate maps a non-negative value onto 0-100, reaching 50 at half.
+func saturate(value, half float64) float64 {
+    if value <= 0 {
+        return 0
+    }
+    return math.Round(100*value/(value+half)*10) / 10
+}
+
+func diffSizeRisk(request models.AnalysisRequest) models.RiskFactor {
+    stats := make(map[string]utils.DiffStat)
+    base, head := request.Event.BaseCommit, request.Event.HeadCommit
+    if head == "" {
+        head = "HEAD"
+    }
+    if base == "" {
+        base = head + "~1"
+    }
+    if s, err := utils.GetDiffStats(request.RepoPath, base, head); err == nil {
+        stats = s
+    } else {
+        utils.LogWithLocation(utils.Debug, "Falling back to file line counts for diff size: %v", err)
+    }
+
+    added, removed := 0, 0
+    for _, file := range request.Files {
+        if s, ok := stats[file.Path]; ok {
+            added += s.Added
+            removed += s.Removed
+            continue
+        }
+        added += file.LinesAdded + file.LinesModified
+        removed += file.LinesRemoved
+    }
+
+    return models.RiskFactor{
+        Name:   riskDiffSize,
+        Score:  saturate(float64(added+removed), 400),
+        Detail: fmt.Sprintf("+%d/-%d lines across %d files", added, removed, len(request.Files)),
+    }
+}
+
+func hotspotRisk(history map[string]utils.FileHistory, paths []string, months int) models.RiskFactor {
+    type hotspot struct {
+        path    string
+        commits int
+    }
+    var spots []hotspot
+    for _, path := range paths {
+        if h, ok := history[path]; ok && h.Commits > 0 {
+            spots = append(spots, hotspot{path, h.Commits})
+        }
+    }
+    sort.Slice(spots, func(i, j int) bool { return spots[i].commits > spots[j].commits })
+
+    if len(spots) == 0 {
+        return models.RiskFactor{
+            Name:   riskHotspots,
+            Detail: fmt.Sprintf("no changed file was modified in the last %d months", months),
+        }
+    }
+
+    var top []string
+    for i := 0; i < len(spots) && i < 3; i++ {
+        top = append(top, fmt.Sprintf("%s (%d)", spots[i].path, spots[i].commits))
+    }
+
+    return models.RiskFactor{
+        Name:   riskHotspots,
+        Score:  saturate(float64(spots[0].commits), 10),
+        Detail: fmt.Sprintf("commits in the last %d months: %s", months, strings.Join(top, ", ")),
+    }
+}
+
+// ownershipRisk rates how diffuse ownership of the changed files is: files
+// where no author made most of the recent commits are riskier to change.
+func ownershipRisk(history map[string]utils.FileHistory, paths []string) models.RiskFactor {
+    total, counted := 0.0, 0
+    for _, path := range paths {
+        h, ok := history[path]
+        if !ok || h.Commits == 0 {
+            continue
+        }
+        topAuthor := 0
+        for _, commits := range h.Authors {
+            if commits > topAuthor {
+                topAuthor = commits
+            }
+        }
+        total += float64(topAuthor) / float64(h.Commits)
+        counted++
+    }
+
+    if counted == 0 {
+        return models.RiskFactor{Name: riskOwnership, Detail: "no recent history for the changed files"}
+    }
+
+    share := total / float64(counted)
+    return models.RiskFactor{
+        Name:   riskOwnership,
+        Score:  math.Round((1-share)*1000) / 10,
+        Detail: fmt.Sprintf("top author made %.0f%% of recent commits on average over %d files", share*100, counted),
+    }
+}
+
+func testChangeRisk(paths []string) models.RiskFactor {
+    sources, tests := 0, 0
+    for _, path := range paths {
+        switch {
+        case isTestFile(path):
+            tests++
+        case riskSourceLanguages[models.DetectLanguageFromFile(path)]:
+            sources++
+        }
+    }
+
+    if sources == 0 {
+        return models.RiskFactor{Name: riskTests, Detail: "no source files changed"}
+    }
+
+    ratio := math.Min(1, float64(tests)/float64(sources))
+    return models.RiskFactor{
+        Name:   riskTests,
+        Score:  math.Round((1-ratio)*1000) / 10,
+        Detail: fmt.Sprintf("%d test files changed alongside %d source files", tests, sources),
+    }
+}
+
+// isTestFile recognizes test files by the naming conventions of the
+// supported languages.
+func isTestFile(path string) bool {
+    base := strings.ToLower(filepath.Base(path))
+    dir := "/" + strings.ToLower(filepath.ToSlash(filepath.Dir(path))) + "/"
+
+    switch {
+    case strings.HasSuffix(base, "_test.go"),
+        strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+        strings.HasSuffix(base, "_test.py"),
+        strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+        strings.HasSuffix(base, "test.java"), strings.HasSuffix(base, "tests.java"),
+        strings.HasSuffix(base, "_spec.rb"):
+        return true
+    }
+    return strings.Contains(dir, "/test/") || strings.Contains(dir, "/tests/") || strings.Contains(dir, "/__tests__/")
+}
+
+// findingsRisk weights each active finding by severity and type, using the
+// same weights that order issues in the review.
+func (ra *ResultAggregator) findingsRisk(result *models.AnalysisResult) models.RiskFactor {
+    weighted := 0
+    count := 0
+    for _, issue := range result.Issues {
+        if issue.Status == models.StatusSuppressed {
+            continue
+        }
+        weighted += (ra.getSeverityWeight(issue.Severity) + 1) * (ra.getTypeWeight(issue.Type) + 2)
+        count++
+    }
+
+    return models.RiskFactor{
+        Name:   riskFindings,
+        Score:  saturate(float64(weighted), 50),
+        Detail: fmt.Sprintf("%d active findings with weighted total %d", count, weighted),
+    }
+}
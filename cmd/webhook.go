@@ -11,10 +11,13 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/euclidstellar/gollora/internal/agent"
+	"github.com/euclidstellar/gollora/internal/export"
+	"github.com/euclidstellar/gollora/internal/history"
 	"github.com/euclidstellar/gollora/internal/models"
 	"github.com/euclidstellar/gollora/internal/utils"
 	"github.com/gorilla/websocket"
@@ -60,6 +63,10 @@ func (wh *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		wh.handleGenericWebhook(w, r)
 	case path == "health" || path == "health/":
 		wh.handleHealthCheck(w, r)
+	case path == "api/history":
+		wh.handleHistoryQuery(w, r)
+	case strings.HasPrefix(path, "reports/"):
+		wh.handleReport(w, r)
 	default:
 		http.NotFound(w, r)
 	}
@@ -78,6 +85,7 @@ func (wh *WebhookHandler) handleAnalyzeWebsocket(w http.ResponseWriter, r *http.
 	defer conn.Close()
 
 	sendMessage := func(msgType string, message string) {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
 		conn.WriteJSON(map[string]string{"type": msgType, "message": message})
 	}
 
@@ -125,26 +133,96 @@ func (wh *WebhookHandler) handleAnalyzeWebsocket(w http.ResponseWriter, r *http.
 		RepoPath: repoPath,
 		Files:    files,
 		Settings: models.AnalysisSettings{
-			EnableAI:      wh.config.AI.Enabled,
-			ExportFormats: []string{"markdown"}, // We'll generate a markdown report
+			EnableAI:        wh.config.AI.Enabled,
+			ExportFormats:   []string{"markdown"}, // We'll generate a markdown report
+			ExportThreshold: wh.config.Thresholds.Export,
+			GateThreshold:   wh.config.Thresholds.Gate,
 		},
 	}
 
+	// 3. Stream findings as they are produced, then the summary
 	sendMessage("status", "Analyzing code... This may take a moment.")
-	result, err := engine.Analyze(ctx, request)
-	if err != nil {
+	if _, err := engine.AnalyzeStream(ctx, request, &analyzeStream{conn: conn}); err != nil {
 		sendMessage("error", fmt.Sprintf("Analysis failed: %v", err))
 		return
 	}
+}
+
+// streamWriteTimeout bounds each websocket write, so a client that stops
+// reading fails the stream instead of stalling it.
+const streamWriteTimeout = 10 * time.Second
+
+// analyzeStream sends each finding to the analyze page as an "issue" message
+// and the summary record as a final "summary" message. The engine never calls
+// it concurrently, so the connection has a single writer.
+type analyzeStream struct {
+	conn *websocket.Conn
+}
+
+func (s *analyzeStream) Issue(issue models.CodeIssue) error {
+	return s.send("issue", issue)
+}
 
-	// 3. Generate and send the full JSON report
-	resultJSON, err := json.Marshal(result)
+func (s *analyzeStream) Finish(result *models.AnalysisResult) error {
+	return s.send("summary", export.NewSummaryRecord(result))
+}
+
+func (s *analyzeStream) send(msgType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
 	if err != nil {
-		sendMessage("error", fmt.Sprintf("Failed to serialize result: %v", err))
+		return fmt.Errorf("failed to serialize %s: %v", msgType, err)
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(map[string]string{"type": msgType, "message": string(data)})
+}
+
+// handleReport serves a single exported report from the local sink's
+// directory so the signed links in PR comments resolve when export.base_url
+// points here. Requests need a valid link signature or the API token, and
+// only plain file names are served, so the directory can't be listed.
+func (wh *WebhookHandler) handleReport(w http.ResponseWriter, r *http.Request) {
+	if wh.config.Export.Sink != "" && wh.config.Export.Sink != "local" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	sendMessage("result", string(resultJSON))
+	name := strings.TrimPrefix(r.URL.Path, "/reports/")
+	if name == "" || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, ".") {
+		http.NotFound(w, r)
+		return
+	}
+	if !wh.authorizedAPI(r) && !export.VerifyReportLink(wh.config.Export.SigningKey, name, r.URL.Query(), time.Now()) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	dir := wh.config.Export.OutputDir
+	if dir == "" {
+		dir = export.DefaultOutputDir
+	}
+	path := filepath.Join(dir, name)
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// authorizedAPI reports whether r carries server.api_token as a bearer
+// token. Without a configured token nothing is authorized.
+func (wh *WebhookHandler) authorizedAPI(r *http.Request) bool {
+	token := wh.config.Server.APIToken
+	if token == "" {
+		return false
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && hmac.Equal([]byte(given), []byte(token))
 }
 
 func (wh *WebhookHandler) handleQAPage(w http.ResponseWriter, r *http.Request) {
@@ -383,6 +461,47 @@ func (wh *WebhookHandler) verifyGitHubSignature(signature string, payload []byte
 	return hmac.Equal(mac, expectedMAC)
 }
 
+// handleHistoryQuery reports issue lifecycle counts for a branch, e.g.
+// GET /api/history?repo=owner/name&branch=feature&base=main
+func (wh *WebhookHandler) handleHistoryQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !wh.config.History.Enabled {
+		http.Error(w, "Issue history is disabled", http.StatusNotFound)
+		return
+	}
+	if !wh.authorizedAPI(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	repo, branch := query.Get("repo"), query.Get("branch")
+	if repo == "" || branch == "" {
+		http.Error(w, "repo and branch are required", http.StatusBadRequest)
+		return
+	}
+
+	store, err := history.Open(wh.config.History.Path, wh.config.History.MaxAnalyses)
+	if err != nil {
+		utils.LogWithLocation(utils.Error, "Failed to open issue history: %v", err)
+		http.Error(w, "Failed to open issue history", http.StatusInternalServerError)
+		return
+	}
+	summary, err := store.Summary(repo, branch, query.Get("base"))
+	if err != nil {
+		utils.LogWithLocation(utils.Error, "Failed to query issue history: %v", err)
+		http.Error(w, "Failed to query issue history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
 func (wh *WebhookHandler) extractGitHubEvent(eventType string, payload map[string]interface{}) (models.WebhookEvent, error) {
 	event := models.WebhookEvent{
 		Type:     eventType,
@@ -402,6 +521,7 @@ func (wh *WebhookHandler) extractGitHubEvent(eventType string, payload map[strin
 
 		if defaultBranch, ok := repo["default_branch"].(string); ok {
 			event.Branch = defaultBranch
+			event.BaseBranch = defaultBranch
 		}
 	}
 
@@ -440,6 +560,9 @@ func (wh *WebhookHandler) extractGitHubEvent(eventType string, payload map[strin
 				if sha, ok := base["sha"].(string); ok {
 					event.BaseCommit = sha
 				}
+				if ref, ok := base["ref"].(string); ok {
+					event.BaseBranch = ref
+				}
 			}
 
 			if head, ok := pr["head"].(map[string]interface{}); ok {
@@ -5,11 +5,8 @@ go 1.24.0
 toolchain go1.24.1
 
 require (
-	github.com/gomarkdown/markdown v0.0.0-20250311123330-531bef5e742b
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/gorilla/websocket v1.5.3
+	golang.org/x/mod v0.28.0
 	gopkg.in/yaml.v3 v3.0.1
 )
-
-require (
-	github.com/gorilla/websocket v1.5.3 // indirect
-	golang.org/x/mod v0.28.0 // indirect
-)
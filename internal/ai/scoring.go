@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+const (
+    defaultScoringBatchSize = 25
+    defaultMaxScoringCalls  = 10
+    defaultScoreCacheSize   = 5000
+    maxScoringSnippet       = 600
+)
+
+// SeverityScore is the model's verdict on one issue.
+type SeverityScore struct {
+    Severity      models.IssueSeverity
+    Justification string
+}
+
+// ScoreCache remembers severity scores by issue fingerprint so an issue seen
+// in an earlier analysis is not sent to the model again. When full, the
+// oldest entries are evicted first.
+type ScoreCache struct {
+    mu      sync.Mutex
+    entries map[string]SeverityScore
+    order   []string
+    limit   int
+}
+
+// NewScoreCache returns a cache holding at most limit scores; limit <= 0
+// uses the default size.
+func NewScoreCache(limit int) *ScoreCache {
+    if limit <= 0 {
+        limit = defaultScoreCacheSize
+    }
+    return &ScoreCache{
+        entries: make(map[string]SeverityScore),
+        limit:   limit,
+    }
+}
+
+func (c *ScoreCache) Get(fingerprint string) (SeverityScore, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    score, ok := c.entries[fingerprint]
+    return score, ok
+}
+
+func (c *ScoreCache) Put(fingerprint string, score SeverityScore) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if _, ok := c.entries[fingerprint]; !ok {
+        c.order = append(c.order, fingerprint)
+    }
+    c.entries[fingerprint] = score
+    for len(c.order) > c.limit {
+        delete(c.entries, c.order[0])
+        c.order = c.order[1:]
+    }
+}
+
+// SeverityScorer re-scores issue severities with the AI model, sending many
+// issues per prompt and never making more than maxCalls requests per run.
+type SeverityScorer struct {
+    client    *Client
+    cache     *ScoreCache
+    batchSize int
+    maxCalls  int
+}
+
+// NewSeverityScorer creates a scorer. Non-positive batch sizes and call
+// limits fall back to the defaults.
+func NewSeverityScorer(client *Client, cache *ScoreCache, batchSize, maxCalls int) *SeverityScorer {
+    if batchSize <= 0 {
+        batchSize = defaultScoringBatchSize
+    }
+    if maxCalls <= 0 {
+        maxCalls = defaultMaxScoringCalls
+    }
+    if cache == nil {
+        cache = NewScoreCache(0)
+    }
+    return &SeverityScorer{
+        client:    client,
+        cache:     cache,
+        batchSize: batchSize,
+        maxCalls:  maxCalls,
+    }
+}
+
+// ScoringStats reports how a Score run went.
+type ScoringStats struct {
+    Cached  int
+    Scored  int
+    Skipped int
+    Calls   int
+    Invalid int
+}
+
+// Score returns scores keyed by fingerprint for the given issues. Cached
+// scores are reused; the rest are sent in batches until the call budget is
+// spent or ctx is done, and whatever is left is skipped. Replies with a
+// severity outside the enum are discarded.
+func (s *SeverityScorer) Score(ctx context.Context, issues []models.CodeIssue) (map[string]SeverityScore, ScoringStats, error) {
+    scores := make(map[string]SeverityScore)
+    var stats ScoringStats
+    var pending []models.CodeIssue
+    seen := make(map[string]bool)
+
+    for _, issue := range issues {
+        if issue.Fingerprint == "" || seen[issue.Fingerprint] {
+            continue
+        }
+        seen[issue.Fingerprint] = true
+        if score, ok := s.cache.Get(issue.Fingerprint); ok {
+            scores[issue.Fingerprint] = score
+            stats.Cached++
+            continue
+        }
+        pending = append(pending, issue)
+    }
+
+    for start := 0; start < len(pending); start += s.batchSize {
+        if stats.Calls >= s.maxCalls {
+            stats.Skipped += len(pending) - start
+            break
+        }
+        if err := ctx.Err(); err != nil {
+            stats.Skipped += len(pending) - start
+            return scores, stats, err
+        }
+
+        end := start + s.batchSize
+        if end > len(pending) {
+            end = len(pending)
+        }
+        batch := pending[start:end]
+
+        stats.Calls++
+        response, err := s.client.GenerateContent(ctx, buildScoringPrompt(batch))
+        if err != nil {
+            if ctx.Err() != nil {
+                stats.Skipped += len(pending) - start
+                return scores, stats, ctx.Err()
+            }
+            stats.Skipped += len(batch)
+            continue
+        }
+
+        replies, err := parseScoringResponse(response)
+        if err != nil {
+            stats.Skipped += len(batch)
+            continue
+        }
+
+        for _, reply := range replies {
+            if reply.ID < 0 || reply.ID >= len(batch) {
+                stats.Invalid++
+                continue
+            }
+            severity, ok := models.ParseIssueSeverity(reply.Severity)
+            if !ok {
+                stats.Invalid++
+                continue
+            }
+            score := SeverityScore{Severity: severity, Justification: strings.TrimSpace(reply.Reason)}
+            fingerprint := batch[reply.ID].Fingerprint
+            scores[fingerprint] = score
+            s.cache.Put(fingerprint, score)
+            stats.Scored++
+        }
+    }
+
+    return scores, stats, nil
+}
+
+type scoringReply struct {
+    ID       int    `json:"id"`
+    Severity string `json:"severity"`
+    Reason   string `json:"reason"`
+}
+
+func buildScoringPrompt(batch []models.CodeIssue) string {
+    var sb strings.Builder
+    sb.WriteString(`You are a code quality expert. Score the severity of each code issue below.
+Rate each one as "CRITICAL", "ERROR", "WARNING", "INFO" or "HINT".
+A hardcoded secret is CRITICAL. A syntax error is an ERROR. A stylistic issue is INFO.
+Respond with only a JSON array holding one object per issue, with the issue's "id", the "severity" and a one-sentence "reason".
+Example: [{"id": 0, "severity": "WARNING", "reason": "This could lead to a nil dereference if the lookup fails."}]
+
+`)
+    for i, issue := range batch {
+        sb.WriteString(fmt.Sprintf("Issue %d:\n", i))
+        sb.WriteString(fmt.Sprintf("Tool: %s\n", issue.Tool))
+        sb.WriteString(fmt.Sprintf("Current severity: %s\n", issue.Severity))
+        sb.WriteString(fmt.Sprintf("File: %s\nLine: %d\n", issue.File, issue.Line))
+        sb.WriteString(fmt.Sprintf("Description: %s\n", issue.Description))
+        if issue.Code != "" {
+            code := issue.Code
+            if len(code) > maxScoringSnippet {
+                code = code[:maxScoringSnippet] + "..."
+            }
+            sb.WriteString("Code:\n---\n" + code + "\n---\n")
+        }
+        sb.WriteString("\n")
+    }
+    sb.WriteString("Your response:")
+    return sb.String()
+}
+
+func parseScoringResponse(response string) ([]scoringReply, error) {
+    response = strings.TrimSpace(response)
+    response = strings.TrimPrefix(response, "```json")
+    response = strings.TrimPrefix(response, "```")
+    response = strings.TrimSuffix(response, "```")
+
+    start := strings.Index(response, "[")
+    end := strings.LastIndex(response, "]")
+    if start == -1 || end < start {
+        return nil, fmt.Errorf("no JSON array in scoring response")
+    }
+
+    var replies []scoringReply
+    if err := json.Unmarshal([]byte(response[start:end+1]), &replies); err != nil {
+        return nil, fmt.Errorf("failed to parse scoring response: %v", err)
+    }
+    return replies, nil
+}
@@ -0,0 +1,92 @@
+package analyzers
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// runToolCommand executes an analysis tool in dir and returns its stdout.
+// Linters exit non-zero when they report findings, so a failed exit status is
+// logged rather than treated as fatal; only a missing binary is an error.
+func runToolCommand(ctx context.Context, dir, command string, args []string) ([]byte, error) {
+    cmd := exec.CommandContext(ctx, command, args...)
+    cmd.Dir = dir
+
+    var stdout, stderr bytes.Buffer
+    cmd.Stdout = &stdout
+    cmd.Stderr = &stderr
+
+    if err := cmd.Run(); err != nil {
+        if _, ok := err.(*exec.ExitError); !ok {
+            return nil, err
+        }
+        utils.LogWithLocation(utils.Debug, "%s exited with %v", command, err)
+    }
+
+    if stderr.Len() > 0 {
+        utils.LogWithLocation(utils.Debug, "%s stderr: %s", command, stderr.String())
+    }
+
+    return stdout.Bytes(), nil
+}
+
+// repoRelativePath converts a path reported by a tool into a slash-separated
+// path relative to the repository root, which is how changed files are keyed.
+func repoRelativePath(repoPath, path string) string {
+    path = strings.TrimPrefix(path, "file://")
+    if filepath.IsAbs(path) {
+        if abs, err := filepath.Abs(repoPath); err == nil {
+            if rel, err := filepath.Rel(abs, path); err == nil && !strings.HasPrefix(rel, "..") {
+                path = rel
+            }
+        }
+    }
+    return filepath.ToSlash(strings.TrimPrefix(path, "./"))
+}
+
+// matchChangedFile resolves a path that may only be a suffix of the real file
+// (e.g. a package-relative source path) against the files under analysis.
+func matchChangedFile(path string, files []string) string {
+    path = filepath.ToSlash(path)
+    for _, file := range files {
+        if file == path {
+            return file
+        }
+    }
+    for _, file := range files {
+        if strings.HasSuffix(file, "/"+path) {
+            return file
+        }
+    }
+    return path
+}
+
+// shortenText truncates a message so it fits in an issue title.
+func shortenText(text string, max int) string {
+    text = strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+    if len(text) <= max {
+        return text
+    }
+    return text[:max-3] + "..."
+}
+
+// lintLevelSeverity maps the error/warning/info/style levels shared by
+// shellcheck, hadolint and similar linters.
+func lintLevelSeverity(level string) models.IssueSeverity {
+    switch strings.ToLower(level) {
+    case "error":
+        return models.Error
+    case "warning":
+        return models.Warning
+    case "info":
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
@@ -0,0 +1,294 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// CppAnalyzer analyzes C and C++ code using clang-tidy and cppcheck.
+type CppAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewCppAnalyzer creates a new CppAnalyzer.
+func NewCppAnalyzer(tools []models.Tool) *CppAnalyzer {
+    return &CppAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured tools over the changed C/C++ files.
+func (a *CppAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var cFiles []string
+    for _, file := range files {
+        switch models.DetectLanguageFromFile(file.Path) {
+        case "c", "cpp":
+            cFiles = append(cFiles, file.Path)
+        }
+    }
+
+    if len(cFiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d C/C++ files", len(cFiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        var issues []models.CodeIssue
+        var err error
+
+        switch tool.Name {
+        case "clang-tidy":
+            issues, err = a.runClangTidy(ctx, repoPath, cFiles, tool)
+        case "cppcheck":
+            issues, err = a.runCppcheck(ctx, repoPath, cFiles, tool)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown C/C++ tool: %s", tool.Name)
+            continue
+        }
+
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Error running %s: %v", tool.Name, err)
+            continue
+        }
+        utils.LogWithLocation(utils.Info, "Found %d issues from %s", len(issues), tool.Name)
+        allIssues = append(allIssues, issues...)
+    }
+
+    return allIssues, nil
+}
+
+// compileCommandsDirs lists the usual places a build writes compile_commands.json.
+var compileCommandsDirs = []string{".", "build", "out", "cmake-build-debug", "cmake-build-release", "builddir"}
+
+func findCompileCommands(repoPath string) string {
+    for _, dir := range compileCommandsDirs {
+        if _, err := os.Stat(filepath.Join(repoPath, dir, "compile_commands.json")); err == nil {
+            return dir
+        }
+    }
+    return ""
+}
+
+// runClangTidy runs clang-tidy with the compilation database when one exists;
+// otherwise it passes an empty "--" so clang-tidy doesn't search for one.
+func (a *CppAnalyzer) runClangTidy(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    dbDir := findCompileCommands(repoPath)
+    if dbDir != "" && !containsArg(args, "-p") {
+        args = append(args, "-p", dbDir)
+    }
+    args = append(args, files...)
+    if dbDir == "" && !containsArg(args, "--") {
+        args = append(args, "--")
+    }
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseClangTidyOutput(string(output), repoPath), nil
+}
+
+// clangTidyDiagnostic matches lines such as:
+// /src/main.cpp:12:5: warning: use nullptr [modernize-use-nullptr]
+var clangTidyDiagnostic = regexp.MustCompile(`^(.+?):(\d+):(\d+): (warning|error): (.*?) \[([\w.,-]+)\]$`)
+
+// parseClangTidyOutput parses clang-tidy diagnostics, skipping notes and
+// headers outside the repository.
+func parseClangTidyOutput(output, repoPath string) []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, line := range strings.Split(output, "\n") {
+        m := clangTidyDiagnostic.FindStringSubmatch(strings.TrimSpace(line))
+        if m == nil {
+            continue
+        }
+
+        path := repoRelativePath(repoPath, m[1])
+        if filepath.IsAbs(path) {
+            continue
+        }
+
+        lineNum, _ := strconv.Atoi(m[2])
+        colNum, _ := strconv.Atoi(m[3])
+        ruleID := strings.Split(m[6], ",")[0]
+
+        severity := models.Warning
+        if m[4] == "error" {
+            severity = models.Error
+        }
+
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(m[5], 60)),
+            Description: m[5],
+            File:        path,
+            Line:        lineNum,
+            Column:      colNum,
+            Severity:    severity,
+            Type:        clangTidyIssueType(ruleID),
+            Tool:        "clang-tidy",
+            RuleID:      ruleID,
+            URL:         clangTidyDocURL(ruleID),
+        })
+    }
+    return issues
+}
+
+func clangTidyIssueType(ruleID string) models.IssueType {
+    switch {
+    case strings.HasPrefix(ruleID, "cert-"), strings.HasPrefix(ruleID, "clang-analyzer-security"),
+        strings.Contains(ruleID, "insecure"):
+        return models.Security
+    case strings.HasPrefix(ruleID, "bugprone-"), strings.HasPrefix(ruleID, "clang-analyzer-"),
+        strings.HasPrefix(ruleID, "clang-diagnostic-"), strings.HasPrefix(ruleID, "concurrency-"):
+        return models.Bug
+    case strings.HasPrefix(ruleID, "performance-"):
+        return models.Performance
+    case strings.HasPrefix(ruleID, "modernize-"), strings.HasPrefix(ruleID, "cppcoreguidelines-"),
+        strings.HasPrefix(ruleID, "misc-"), strings.HasPrefix(ruleID, "hicpp-"):
+        return models.Maintainability
+    default:
+        return models.CodeStyle
+    }
+}
+
+func clangTidyDocURL(ruleID string) string {
+    idx := strings.Index(ruleID, "-")
+    if idx == -1 || strings.HasPrefix(ruleID, "clang-") {
+        return ""
+    }
+    return fmt.Sprintf("https://clang.llvm.org/extra/clang-tidy/checks/%s/%s.html", ruleID[:idx], ruleID[idx+1:])
+}
+
+// runCppcheck runs cppcheck with XML output. cppcheck writes its report to
+// stderr, so it is redirected to a temporary file instead.
+func (a *CppAnalyzer) runCppcheck(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    reportFile, err := os.CreateTemp("", "gollora-cppcheck-*.xml")
+    if err != nil {
+        return nil, fmt.Errorf("failed to create report file: %v", err)
+    }
+    reportFile.Close()
+    defer os.Remove(reportFile.Name())
+
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "--xml") {
+        args = append(args, "--xml", "--xml-version=2")
+    }
+    args = append(args, "--output-file="+reportFile.Name())
+    args = append(args, files...)
+
+    if _, err := runToolCommand(ctx, repoPath, tool.Command, args); err != nil {
+        return nil, err
+    }
+
+    data, err := os.ReadFile(reportFile.Name())
+    if err != nil {
+        return nil, fmt.Errorf("failed to read cppcheck report: %v", err)
+    }
+    return parseCppcheckXML(data, repoPath)
+}
+
+type cppcheckReport struct {
+    Errors []struct {
+        ID        string `xml:"id,attr"`
+        Severity  string `xml:"severity,attr"`
+        Msg       string `xml:"msg,attr"`
+        Verbose   string `xml:"verbose,attr"`
+        CWE       string `xml:"cwe,attr"`
+        Locations []struct {
+            File   string `xml:"file,attr"`
+            Line   int    `xml:"line,attr"`
+            Column int    `xml:"column,attr"`
+        } `xml:"location"`
+    } `xml:"errors>error"`
+}
+
+// parseCppcheckXML converts a cppcheck XML version 2 report into CodeIssues.
+func parseCppcheckXML(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    if len(strings.TrimSpace(string(data))) == 0 {
+        return nil, nil
+    }
+
+    var report cppcheckReport
+    if err := xml.Unmarshal(trimToXML(data), &report); err != nil {
+        return nil, fmt.Errorf("failed to parse cppcheck output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, e := range report.Errors {
+        // Configuration notices carry no location and aren't code findings.
+        if len(e.Locations) == 0 || e.ID == "missingIncludeSystem" || e.ID == "checkersReport" {
+            continue
+        }
+
+        description := e.Verbose
+        if description == "" {
+            description = e.Msg
+        }
+
+        issue := models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", e.ID, shortenText(e.Msg, 60)),
+            Description: description,
+            File:        repoRelativePath(repoPath, e.Locations[0].File),
+            Line:        e.Locations[0].Line,
+            Column:      e.Locations[0].Column,
+            Severity:    cppcheckSeverity(e.Severity),
+            Type:        cppcheckIssueType(e.Severity, e.CWE),
+            Tool:        "cppcheck",
+            RuleID:      e.ID,
+        }
+        if e.CWE != "" && e.CWE != "0" {
+            issue.Metadata = map[string]string{"cwe": "CWE-" + e.CWE}
+        }
+        issues = append(issues, issue)
+    }
+
+    return issues, nil
+}
+
+func cppcheckSeverity(severity string) models.IssueSeverity {
+    switch severity {
+    case "error":
+        return models.Error
+    case "warning":
+        return models.Warning
+    case "performance", "portability", "style":
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
+
+func cppcheckIssueType(severity, cwe string) models.IssueType {
+    switch cwe {
+    case "119", "120", "121", "122", "125", "134", "416", "476", "787":
+        return models.Security
+    }
+    switch severity {
+    case "error", "warning":
+        return models.Bug
+    case "performance":
+        return models.Performance
+    case "portability":
+        return models.Maintainability
+    default:
+        return models.CodeStyle
+    }
+}
@@ -0,0 +1,137 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// DockerfileAnalyzer analyzes Dockerfiles using hadolint.
+type DockerfileAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewDockerfileAnalyzer creates a new DockerfileAnalyzer.
+func NewDockerfileAnalyzer(tools []models.Tool) *DockerfileAnalyzer {
+    return &DockerfileAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured tools over the changed Dockerfiles.
+func (a *DockerfileAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var dockerfiles []string
+    for _, file := range files {
+        if models.DetectLanguageFromFile(file.Path) == "dockerfile" {
+            dockerfiles = append(dockerfiles, file.Path)
+        }
+    }
+
+    if len(dockerfiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d Dockerfiles", len(dockerfiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        switch tool.Name {
+        case "hadolint":
+            issues, err := a.runHadolint(ctx, repoPath, dockerfiles, tool)
+            if err != nil {
+                utils.LogWithLocation(utils.Error, "Error running hadolint: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "Found %d issues from hadolint", len(issues))
+            allIssues = append(allIssues, issues...)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown Dockerfile tool: %s", tool.Name)
+        }
+    }
+
+    return allIssues, nil
+}
+
+func (a *DockerfileAnalyzer) runHadolint(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "-f") && !containsArg(args, "--format") {
+        args = append(args, "-f", "json")
+    }
+    args = append(args, files...)
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseHadolintJSON(output, repoPath)
+}
+
+// parseHadolintJSON converts hadolint's JSON output into CodeIssues. Rules
+// prefixed with SC come from the shellcheck pass hadolint runs over RUN lines.
+func parseHadolintJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    if len(strings.TrimSpace(string(data))) == 0 {
+        return nil, nil
+    }
+
+    var results []struct {
+        Code    string `json:"code"`
+        Column  int    `json:"column"`
+        File    string `json:"file"`
+        Level   string `json:"level"`
+        Line    int    `json:"line"`
+        Message string `json:"message"`
+    }
+    if err := json.Unmarshal(data, &results); err != nil {
+        return nil, fmt.Errorf("failed to parse hadolint output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, r := range results {
+        url := "https://github.com/hadolint/hadolint/wiki/" + r.Code
+        if strings.HasPrefix(r.Code, "SC") {
+            url = "https://www.shellcheck.net/wiki/" + r.Code
+        }
+
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", r.Code, shortenText(r.Message, 60)),
+            Description: r.Message,
+            File:        repoRelativePath(repoPath, r.File),
+            Line:        r.Line,
+            Column:      r.Column,
+            Severity:    lintLevelSeverity(r.Level),
+            Type:        hadolintIssueType(r.Code, r.Message),
+            Tool:        "hadolint",
+            RuleID:      r.Code,
+            URL:         url,
+        })
+    }
+
+    return issues, nil
+}
+
+func hadolintIssueType(code, message string) models.IssueType {
+    message = strings.ToLower(message)
+    switch {
+    case code == "DL3002", code == "DL3004", strings.Contains(message, "secret"),
+        strings.Contains(message, "root"), strings.Contains(message, "sudo"):
+        return models.Security
+    case code == "DL3006", code == "DL3007", code == "DL3008", code == "DL3013",
+        code == "DL3016", code == "DL3018", code == "DL3028":
+        return models.Dependency
+    case strings.HasPrefix(code, "SC"):
+        return models.Bug
+    default:
+        return models.Maintainability
+    }
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,10 @@ import (
 	"github.com/euclidstellar/gollora/internal/utils"
 )
 
+// checkCodePattern extracts the check code some linters (gosec, staticcheck)
+// prefix their messages with, e.g. "G101" or "SA4006".
+var checkCodePattern = regexp.MustCompile(`^(G|SA|S|ST|QF)\d{3,4}\b`)
+
 type GolangAnalyzer struct {
     tools []models.Tool
 }
@@ -137,7 +142,7 @@ func (a *GolangAnalyzer) runGolangCILint(ctx context.Context, repoPath string, f
                     }
                 }
             
-                filePath = filepath.Base(filePath)
+                filePath = repoRelativePath(repoPath, filePath)
 
                 severity := models.Error 
                 issueType := a.mapIssueType(linterName, message)
@@ -152,6 +157,7 @@ func (a *GolangAnalyzer) runGolangCILint(ctx context.Context, repoPath string, f
                     Type:        issueType,
                     Tool:        "golangci-lint",
                     RuleID:      linterName,
+                    Rule:        checkCodePattern.FindString(message),
                 })
                 
                 utils.LogWithLocation(utils.Info, "Found issue: %s:%d:%d - %s (%s)", 
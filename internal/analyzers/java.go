@@ -1,22 +1,407 @@
 package analyzers
-import "fmt"
-// JavaAnalyzer analyzes Java code for issues
-// java is a placeholder function for analyzing Java code.
 
-func java(){
-    fmt.Print(`
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
 
-                    // For Future Implementation
-    
-TODO: Implement the Java analyzer functionality, similar to the GolangAnalyzer.
-TODO: Add logic to filter Java files (e.g., .java) and prepare them for analysis.
-TODO: Integrate tools like Checkstyle, PMD, or SpotBugs for static code analysis.
-TODO: Ensure proper configuration setup for the tools, such as default or user-provided configurations.
-TODO: Parse the output of the tools to extract meaningful code issues and map them to the models.CodeIssue structure.
-TODO: Handle edge cases, such as missing configurations or unsupported file types, and provide appropriate error handling.
-TODO: Document the expected behavior, parameters, and return values of the function once implemented.
-    
-    `)
+// JavaAnalyzer analyzes Java code using Checkstyle, PMD and SpotBugs.
+type JavaAnalyzer struct {
+    tools []models.Tool
 }
 
+// NewJavaAnalyzer creates a new JavaAnalyzer.
+func NewJavaAnalyzer(tools []models.Tool) *JavaAnalyzer {
+    return &JavaAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured Java tools over the changed .java files.
+func (a *JavaAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var javaFiles []string
+    for _, file := range files {
+        if strings.HasSuffix(file.Path, ".java") {
+            javaFiles = append(javaFiles, file.Path)
+        }
+    }
+
+    if len(javaFiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d Java files", len(javaFiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        var issues []models.CodeIssue
+        var err error
+
+        switch tool.Name {
+        case "checkstyle":
+            issues, err = a.runCheckstyle(ctx, repoPath, javaFiles, tool)
+        case "pmd":
+            issues, err = a.runPMD(ctx, repoPath, javaFiles, tool)
+        case "spotbugs":
+            issues, err = a.runSpotBugs(ctx, repoPath, javaFiles, tool)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown Java tool: %s", tool.Name)
+            continue
+        }
+
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Error running %s: %v", tool.Name, err)
+            continue
+        }
+        utils.LogWithLocation(utils.Info, "Found %d issues from %s", len(issues), tool.Name)
+        allIssues = append(allIssues, issues...)
+    }
+
+    return allIssues, nil
+}
+
+// runCheckstyle runs Checkstyle with XML output over the changed files.
+// Without an explicit -c the bundled Google style is used.
+func (a *JavaAnalyzer) runCheckstyle(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "-c") {
+        args = append(args, "-c", "/google_checks.xml")
+    }
+    if !containsArg(args, "-f") {
+        args = append(args, "-f", "xml")
+    }
+    args = append(args, files...)
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseCheckstyleXML(output, repoPath)
+}
+
+// runPMD runs PMD over the changed files and accepts either JSON or XML reports.
+func (a *JavaAnalyzer) runPMD(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "-f") && !containsArg(args, "--format") {
+        args = append(args, "-f", "json")
+    }
+    args = append(args, "-d", strings.Join(files, ","))
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    if looksLikeJSON(output) {
+        return parsePMDJSON(output, repoPath)
+    }
+    return parsePMDXML(output, repoPath)
+}
+
+// runSpotBugs runs SpotBugs with its configured arguments. SpotBugs works on
+// compiled classes, so the args must point at the build output; findings are
+// reported against source paths and matched back to the changed files.
+func (a *JavaAnalyzer) runSpotBugs(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    output, err := runToolCommand(ctx, repoPath, tool.Command, tool.Args)
+    if err != nil {
+        return nil, err
+    }
+    if looksLikeJSON(output) {
+        return parseSARIF(output, repoPath, "spotbugs", files)
+    }
+    return parseSpotBugsXML(output, files)
+}
+
+type checkstyleReport struct {
+    Files []struct {
+        Name   string `xml:"name,attr"`
+        Errors []struct {
+            Line     int    `xml:"line,attr"`
+            Column   int    `xml:"column,attr"`
+            Severity string `xml:"severity,attr"`
+            Message  string `xml:"message,attr"`
+            Source   string `xml:"source,attr"`
+        } `xml:"error"`
+    } `xml:"file"`
+}
+
+// parseCheckstyleXML converts a Checkstyle XML report into CodeIssues.
+func parseCheckstyleXML(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var report checkstyleReport
+    if err := xml.Unmarshal(trimToXML(data), &report); err != nil {
+        return nil, fmt.Errorf("failed to parse checkstyle output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, file := range report.Files {
+        path := repoRelativePath(repoPath, file.Name)
+        for _, e := range file.Errors {
+            ruleID := checkstyleRuleName(e.Source)
+            issues = append(issues, models.CodeIssue{
+                Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(e.Message, 60)),
+                Description: e.Message,
+                File:        path,
+                Line:        e.Line,
+                Column:      e.Column,
+                Severity:    checkstyleSeverity(e.Severity),
+                Type:        checkstyleIssueType(e.Source),
+                Tool:        "checkstyle",
+                RuleID:      ruleID,
+                Rule:        e.Source,
+            })
+        }
+    }
+    return issues, nil
+}
+
+// checkstyleRuleName shortens a fully qualified check class such as
+// com.puppycrawl.tools.checkstyle.checks.whitespace.WhitespaceAroundCheck.
+func checkstyleRuleName(source string) string {
+    if idx := strings.LastIndex(source, "."); idx != -1 {
+        source = source[idx+1:]
+    }
+    return strings.TrimSuffix(source, "Check")
+}
+
+func checkstyleSeverity(severity string) models.IssueSeverity {
+    switch strings.ToLower(severity) {
+    case "error":
+        return models.Error
+    case "warning":
+        return models.Warning
+    case "info":
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
+
+func checkstyleIssueType(source string) models.IssueType {
+    switch {
+    case strings.Contains(source, ".javadoc."):
+        return models.Documentation
+    case strings.Contains(source, ".coding."), strings.Contains(source, ".metrics."),
+        strings.Contains(source, ".design."), strings.Contains(source, ".sizes."):
+        return models.Maintainability
+    default:
+        return models.CodeStyle
+    }
+}
+
+type pmdViolation struct {
+    BeginLine   int    `json:"beginline" xml:"beginline,attr"`
+    BeginColumn int    `json:"begincolumn" xml:"begincolumn,attr"`
+    EndLine     int    `json:"endline" xml:"endline,attr"`
+    Description string `json:"description" xml:",chardata"`
+    Rule        string `json:"rule" xml:"rule,attr"`
+    RuleSet     string `json:"ruleset" xml:"ruleset,attr"`
+    Priority    int    `json:"priority" xml:"priority,attr"`
+    URL         string `json:"externalInfoUrl" xml:"externalInfoUrl,attr"`
+}
+
+type pmdFile struct {
+    Name       string         `json:"filename" xml:"name,attr"`
+    Violations []pmdViolation `json:"violations" xml:"violation"`
+}
+
+// parsePMDJSON converts PMD's JSON renderer output into CodeIssues.
+func parsePMDJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var report struct {
+        Files []pmdFile `json:"files"`
+    }
+    if err := json.Unmarshal(data, &report); err != nil {
+        return nil, fmt.Errorf("failed to parse PMD JSON output: %v", err)
+    }
+    return pmdIssues(report.Files, repoPath), nil
+}
+
+// parsePMDXML converts PMD's XML renderer output into CodeIssues.
+func parsePMDXML(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var report struct {
+        Files []pmdFile `xml:"file"`
+    }
+    if err := xml.Unmarshal(trimToXML(data), &report); err != nil {
+        return nil, fmt.Errorf("failed to parse PMD XML output: %v", err)
+    }
+    return pmdIssues(report.Files, repoPath), nil
+}
+
+func pmdIssues(files []pmdFile, repoPath string) []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, file := range files {
+        path := repoRelativePath(repoPath, file.Name)
+        for _, v := range file.Violations {
+            description := strings.TrimSpace(v.Description)
+            issues = append(issues, models.CodeIssue{
+                Title:       fmt.Sprintf("%s: %s", v.Rule, shortenText(description, 60)),
+                Description: description,
+                File:        path,
+                Line:        v.BeginLine,
+                Column:      v.BeginColumn,
+                EndLine:     v.EndLine,
+                Severity:    pmdPrioritySeverity(v.Priority),
+                Type:        pmdIssueType(v.RuleSet),
+                Tool:        "pmd",
+                RuleID:      v.Rule,
+                Rule:        v.RuleSet,
+                URL:         v.URL,
+            })
+        }
+    }
+    return issues
+}
+
+// pmdPrioritySeverity maps PMD priorities (1 = highest, 5 = lowest).
+func pmdPrioritySeverity(priority int) models.IssueSeverity {
+    switch priority {
+    case 1:
+        return models.Critical
+    case 2:
+        return models.Error
+    case 3:
+        return models.Warning
+    case 4:
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
+
+func pmdIssueType(ruleSet string) models.IssueType {
+    switch strings.ToLower(ruleSet) {
+    case "security":
+        return models.Security
+    case "performance":
+        return models.Performance
+    case "error prone", "errorprone", "multithreading":
+        return models.Bug
+    case "design", "best practices", "bestpractices":
+        return models.Maintainability
+    case "documentation":
+        return models.Documentation
+    default:
+        return models.CodeStyle
+    }
+}
+
+type spotBugsReport struct {
+    Bugs []struct {
+        Type         string `xml:"type,attr"`
+        Priority     int    `xml:"priority,attr"`
+        Rank         int    `xml:"rank,attr"`
+        Category     string `xml:"category,attr"`
+        ShortMessage string `xml:"ShortMessage"`
+        LongMessage  string `xml:"LongMessage"`
+        SourceLines  []struct {
+            SourcePath string `xml:"sourcepath,attr"`
+            Start      string `xml:"start,attr"`
+            Primary    bool   `xml:"primary,attr"`
+        } `xml:"SourceLine"`
+    } `xml:"BugInstance"`
+}
+
+// parseSpotBugsXML converts a SpotBugs XML report into CodeIssues. Source
+// paths are relative to the source root, so they are matched against files.
+func parseSpotBugsXML(data []byte, files []string) ([]models.CodeIssue, error) {
+    var report spotBugsReport
+    if err := xml.Unmarshal(trimToXML(data), &report); err != nil {
+        return nil, fmt.Errorf("failed to parse SpotBugs XML output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, bug := range report.Bugs {
+        var sourcePath string
+        var line int
+        for _, sl := range bug.SourceLines {
+            if sourcePath == "" || sl.Primary {
+                sourcePath = sl.SourcePath
+                line, _ = strconv.Atoi(sl.Start)
+            }
+        }
+
+        description := bug.LongMessage
+        if description == "" {
+            description = bug.ShortMessage
+        }
+
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", bug.Type, shortenText(bug.ShortMessage, 60)),
+            Description: description,
+            File:        matchChangedFile(sourcePath, files),
+            Line:        line,
+            Severity:    spotBugsSeverity(bug.Priority, bug.Rank),
+            Type:        spotBugsIssueType(bug.Category),
+            Tool:        "spotbugs",
+            RuleID:      bug.Type,
+            Rule:        bug.Category,
+        })
+    }
+    return issues, nil
+}
+
+// spotBugsSeverity maps confidence priority (1 = high) and bug rank, where
+// ranks 1-4 are the "scariest" bugs.
+func spotBugsSeverity(priority, rank int) models.IssueSeverity {
+    if rank > 0 && rank <= 4 {
+        return models.Critical
+    }
+    switch priority {
+    case 1:
+        return models.Error
+    case 2:
+        return models.Warning
+    default:
+        return models.Info
+    }
+}
+
+func spotBugsIssueType(category string) models.IssueType {
+    switch strings.ToUpper(category) {
+    case "SECURITY", "MALICIOUS_CODE":
+        return models.Security
+    case "PERFORMANCE":
+        return models.Performance
+    case "CORRECTNESS", "MT_CORRECTNESS":
+        return models.Bug
+    case "BAD_PRACTICE", "STYLE":
+        return models.Maintainability
+    default:
+        return models.CodeStyle
+    }
+}
+
+func containsArg(args []string, name string) bool {
+    for _, arg := range args {
+        if arg == name || strings.HasPrefix(arg, name+"=") {
+            return true
+        }
+    }
+    return false
+}
+
+func looksLikeJSON(data []byte) bool {
+    trimmed := bytes.TrimSpace(data)
+    return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// trimToXML drops any log lines a tool printed before the XML document.
+func trimToXML(data []byte) []byte {
+    if idx := bytes.IndexByte(data, '<'); idx > 0 {
+        return data[idx:]
+    }
+    return data
+}
@@ -0,0 +1,279 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// javaChangedFiles are the files the Java fixtures report against.
+var javaChangedFiles = []string{
+    "src/main/java/com/example/App.java",
+    "src/main/java/com/example/Util.java",
+}
+
+func readJavaFixture(t *testing.T, name string) []byte {
+    t.Helper()
+    data, err := os.ReadFile(filepath.Join("testdata", "java", name))
+    if err != nil {
+        t.Fatalf("failed to read fixture: %v", err)
+    }
+    return data
+}
+
+func TestJavaReportParsers(t *testing.T) {
+    tests := []struct {
+        name    string
+        fixture string
+        parse   func(data []byte) ([]models.CodeIssue, error)
+        want    []models.CodeIssue
+    }{
+        {
+            name:    "checkstyle xml",
+            fixture: "checkstyle.xml",
+            parse: func(data []byte) ([]models.CodeIssue, error) {
+                return parseCheckstyleXML(data, "/repo")
+            },
+            want: []models.CodeIssue{
+                {
+                    Title:       "Indentation: 'method def' child has incorrect indentation level 4, exp...",
+                    Description: "'method def' child has incorrect indentation level 4, expected level should be 2.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        12,
+                    Column:      5,
+                    Severity:    models.Warning,
+                    Type:        models.CodeStyle,
+                    Tool:        "checkstyle",
+                    RuleID:      "Indentation",
+                    Rule:        "com.puppycrawl.tools.checkstyle.checks.indentation.IndentationCheck",
+                },
+                {
+                    Title:       "MissingJavadocMethod: Missing a Javadoc comment.",
+                    Description: "Missing a Javadoc comment.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        20,
+                    Severity:    models.Error,
+                    Type:        models.Documentation,
+                    Tool:        "checkstyle",
+                    RuleID:      "MissingJavadocMethod",
+                    Rule:        "com.puppycrawl.tools.checkstyle.checks.javadoc.MissingJavadocMethodCheck",
+                },
+                {
+                    Title:       "MagicNumber: '42' is a magic number.",
+                    Description: "'42' is a magic number.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        31,
+                    Column:      9,
+                    Severity:    models.Info,
+                    Type:        models.Maintainability,
+                    Tool:        "checkstyle",
+                    RuleID:      "MagicNumber",
+                    Rule:        "com.puppycrawl.tools.checkstyle.checks.coding.MagicNumberCheck",
+                },
+            },
+        },
+        {
+            name:    "pmd json",
+            fixture: "pmd.json",
+            parse: func(data []byte) ([]models.CodeIssue, error) {
+                return parsePMDJSON(data, "/repo")
+            },
+            want: []models.CodeIssue{
+                {
+                    Title:       "UnusedLocalVariable: Avoid unused local variables such as 'count'.",
+                    Description: "Avoid unused local variables such as 'count'.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        14,
+                    Column:      9,
+                    EndLine:     14,
+                    Severity:    models.Warning,
+                    Type:        models.Maintainability,
+                    Tool:        "pmd",
+                    RuleID:      "UnusedLocalVariable",
+                    Rule:        "Best Practices",
+                    URL:         "https://docs.pmd-code.org/pmd-doc-7.0.0/pmd_rules_java_bestpractices.html#unusedlocalvariable",
+                },
+                {
+                    Title:       "HardCodedCryptoKey: Avoid hardcoded passwords.",
+                    Description: "Avoid hardcoded passwords.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        40,
+                    Column:      13,
+                    EndLine:     42,
+                    Severity:    models.Critical,
+                    Type:        models.Security,
+                    Tool:        "pmd",
+                    RuleID:      "HardCodedCryptoKey",
+                    Rule:        "Security",
+                    URL:         "https://docs.pmd-code.org/pmd-doc-7.0.0/pmd_rules_java_security.html#hardcodedcryptokey",
+                },
+            },
+        },
+        {
+            name:    "pmd xml",
+            fixture: "pmd.xml",
+            parse: func(data []byte) ([]models.CodeIssue, error) {
+                return parsePMDXML(data, "/repo")
+            },
+            want: []models.CodeIssue{
+                {
+                    Title:       "CloseResource: Ensure that resources like this FileInputStream object ar...",
+                    Description: "Ensure that resources like this FileInputStream object are closed after use",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        22,
+                    Column:      17,
+                    EndLine:     22,
+                    Severity:    models.Error,
+                    Type:        models.Bug,
+                    Tool:        "pmd",
+                    RuleID:      "CloseResource",
+                    Rule:        "Error Prone",
+                    URL:         "https://docs.pmd-code.org/pmd-doc-7.0.0/pmd_rules_java_errorprone.html#closeresource",
+                },
+                {
+                    Title:       "UselessParentheses: Useless parentheses.",
+                    Description: "Useless parentheses.",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        50,
+                    Column:      5,
+                    EndLine:     50,
+                    Severity:    models.Info,
+                    Type:        models.CodeStyle,
+                    Tool:        "pmd",
+                    RuleID:      "UselessParentheses",
+                    Rule:        "Code Style",
+                    URL:         "https://docs.pmd-code.org/pmd-doc-7.0.0/pmd_rules_java_codestyle.html#uselessparentheses",
+                },
+            },
+        },
+        {
+            name:    "spotbugs xml",
+            fixture: "spotbugs.xml",
+            parse: func(data []byte) ([]models.CodeIssue, error) {
+                return parseSpotBugsXML(data, javaChangedFiles)
+            },
+            want: []models.CodeIssue{
+                {
+                    Title:       "NP_NULL_ON_SOME_PATH: Possible null pointer dereference",
+                    Description: "Possible null pointer dereference of name in com.example.App.greet(String)",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        27,
+                    Severity:    models.Critical,
+                    Type:        models.Bug,
+                    Tool:        "spotbugs",
+                    RuleID:      "NP_NULL_ON_SOME_PATH",
+                    Rule:        "CORRECTNESS",
+                },
+                {
+                    Title:       "DM_DEFAULT_ENCODING: Reliance on default encoding",
+                    Description: "Reliance on default encoding",
+                    File:        "src/main/java/com/example/Util.java",
+                    Line:        11,
+                    Severity:    models.Warning,
+                    Type:        models.CodeStyle,
+                    Tool:        "spotbugs",
+                    RuleID:      "DM_DEFAULT_ENCODING",
+                    Rule:        "I18N",
+                },
+            },
+        },
+        {
+            name:    "spotbugs sarif",
+            fixture: "spotbugs.sarif",
+            parse: func(data []byte) ([]models.CodeIssue, error) {
+                return parseSARIF(data, "/repo", "spotbugs", javaChangedFiles)
+            },
+            want: []models.CodeIssue{
+                {
+                    Title:       "NP_NULL_ON_SOME_PATH: Possible null pointer dereference of name in com.example....",
+                    Description: "Possible null pointer dereference of name in com.example.App.greet(String).",
+                    File:        "src/main/java/com/example/App.java",
+                    Line:        27,
+                    Severity:    models.Error,
+                    Type:        models.CodeStyle,
+                    Tool:        "spotbugs",
+                    RuleID:      "NP_NULL_ON_SOME_PATH",
+                    URL:         "https://spotbugs.readthedocs.io/en/latest/bugDescriptions.html#NP_NULL_ON_SOME_PATH",
+                },
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := tt.parse(readJavaFixture(t, tt.fixture))
+            if err != nil {
+                t.Fatalf("parse failed: %v", err)
+            }
+            if len(got) != len(tt.want) {
+                t.Fatalf("got %d issues, want %d: %+v", len(got), len(tt.want), got)
+            }
+            for i := range tt.want {
+                if !reflect.DeepEqual(got[i], tt.want[i]) {
+                    t.Errorf("issue %d:\ngot  %+v\nwant %+v", i, got[i], tt.want[i])
+                }
+            }
+        })
+    }
+}
+
+func TestJavaReportParsersRejectGarbage(t *testing.T) {
+    tests := []struct {
+        name  string
+        parse func(data []byte) ([]models.CodeIssue, error)
+    }{
+        {"checkstyle xml", func(data []byte) ([]models.CodeIssue, error) { return parseCheckstyleXML(data, "/repo") }},
+        {"pmd json", func(data []byte) ([]models.CodeIssue, error) { return parsePMDJSON(data, "/repo") }},
+        {"pmd xml", func(data []byte) ([]models.CodeIssue, error) { return parsePMDXML(data, "/repo") }},
+        {"spotbugs xml", func(data []byte) ([]models.CodeIssue, error) { return parseSpotBugsXML(data, javaChangedFiles) }},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := tt.parse([]byte("<unterminated")); err == nil {
+                t.Error("expected an error for a truncated report")
+            }
+        })
+    }
+}
+
+func TestPMDPrioritySeverity(t *testing.T) {
+    tests := []struct {
+        priority int
+        want     models.IssueSeverity
+    }{
+        {1, models.Critical},
+        {2, models.Error},
+        {3, models.Warning},
+        {4, models.Info},
+        {5, models.Hint},
+        {0, models.Hint},
+    }
+    for _, tt := range tests {
+        if got := pmdPrioritySeverity(tt.priority); got != tt.want {
+            t.Errorf("pmdPrioritySeverity(%d) = %s, want %s", tt.priority, got, tt.want)
+        }
+    }
+}
+
+func TestSpotBugsSeverity(t *testing.T) {
+    tests := []struct {
+        priority, rank int
+        want           models.IssueSeverity
+    }{
+        {3, 1, models.Critical},
+        {3, 4, models.Critical},
+        {1, 5, models.Error},
+        {2, 10, models.Warning},
+        {3, 20, models.Info},
+        {1, 0, models.Error},
+    }
+    for _, tt := range tests {
+        if got := spotBugsSeverity(tt.priority, tt.rank); got != tt.want {
+            t.Errorf("spotBugsSeverity(%d, %d) = %s, want %s", tt.priority, tt.rank, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,321 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// JavaScriptAnalyzer analyzes JavaScript and TypeScript code using ESLint and tsc.
+type JavaScriptAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewJavaScriptAnalyzer creates a new JavaScriptAnalyzer.
+func NewJavaScriptAnalyzer(tools []models.Tool) *JavaScriptAnalyzer {
+    return &JavaScriptAnalyzer{
+        tools: tools,
+    }
+}
+
+var jsExtensions = map[string]bool{
+    ".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+    ".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+}
+
+// Analyze runs the configured tools over the changed JS/TS files. Files are
+// grouped by their nearest package.json (ESLint) or tsconfig.json (tsc) so
+// each workspace of a monorepo is checked with its own configuration.
+func (a *JavaScriptAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var jsFiles []string
+    for _, file := range files {
+        if jsExtensions[strings.ToLower(filepath.Ext(file.Path))] {
+            jsFiles = append(jsFiles, file.Path)
+        }
+    }
+
+    if len(jsFiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d JavaScript/TypeScript files", len(jsFiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        var issues []models.CodeIssue
+        var err error
+
+        switch tool.Name {
+        case "eslint":
+            issues, err = a.runESLint(ctx, repoPath, jsFiles, tool)
+        case "tsc":
+            issues, err = a.runTSC(ctx, repoPath, jsFiles, tool)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown JavaScript tool: %s", tool.Name)
+            continue
+        }
+
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Error running %s: %v", tool.Name, err)
+            continue
+        }
+        utils.LogWithLocation(utils.Info, "Found %d issues from %s", len(issues), tool.Name)
+        allIssues = append(allIssues, issues...)
+    }
+
+    return allIssues, nil
+}
+
+// runESLint lints each package separately from its own directory so that
+// ESLint picks up the workspace's config and plugins.
+func (a *JavaScriptAnalyzer) runESLint(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var issues []models.CodeIssue
+
+    groups := groupByNearestFile(repoPath, files, "package.json")
+    for _, dir := range sortedKeys(groups) {
+        args := append([]string{}, tool.Args...)
+        if !containsArg(args, "-f") && !containsArg(args, "--format") {
+            args = append(args, "-f", "json")
+        }
+        for _, file := range groups[dir] {
+            args = append(args, relativeTo(dir, file))
+        }
+
+        output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, args)
+        if err != nil {
+            return nil, err
+        }
+        dirIssues, err := parseESLintJSON(output, repoPath)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %v", displayDir(dir), err)
+        }
+        issues = append(issues, dirIssues...)
+    }
+
+    return issues, nil
+}
+
+// runTSC type-checks every project that owns a changed file and keeps the
+// diagnostics that fall inside the changed files.
+func (a *JavaScriptAnalyzer) runTSC(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var issues []models.CodeIssue
+
+    changed := make(map[string]bool)
+    for _, file := range files {
+        changed[file] = true
+    }
+
+    groups := groupByNearestFile(repoPath, files, "tsconfig.json")
+    for _, dir := range sortedKeys(groups) {
+        if _, err := os.Stat(filepath.Join(repoPath, dir, "tsconfig.json")); err != nil {
+            utils.LogWithLocation(utils.Debug, "No tsconfig.json for %s, skipping tsc", displayDir(dir))
+            continue
+        }
+
+        args := append([]string{}, tool.Args...)
+        if !containsArg(args, "--noEmit") {
+            args = append(args, "--noEmit")
+        }
+        if !containsArg(args, "--pretty") {
+            args = append(args, "--pretty", "false")
+        }
+        if !containsArg(args, "-p") && !containsArg(args, "--project") {
+            args = append(args, "-p", "tsconfig.json")
+        }
+
+        output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, args)
+        if err != nil {
+            return nil, err
+        }
+        for _, issue := range parseTSCOutput(string(output), dir) {
+            if changed[issue.File] {
+                issues = append(issues, issue)
+            }
+        }
+    }
+
+    return issues, nil
+}
+
+type eslintResult struct {
+    FilePath string `json:"filePath"`
+    Messages []struct {
+        RuleID   string `json:"ruleId"`
+        Severity int    `json:"severity"`
+        Message  string `json:"message"`
+        Line     int    `json:"line"`
+        Column   int    `json:"column"`
+        Fatal    bool   `json:"fatal"`
+        Fix      *struct {
+            Text string `json:"text"`
+        } `json:"fix"`
+    } `json:"messages"`
+}
+
+// parseESLintJSON converts ESLint's json formatter output into CodeIssues.
+func parseESLintJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var results []eslintResult
+    if err := json.Unmarshal(data, &results); err != nil {
+        return nil, fmt.Errorf("failed to parse ESLint output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, res := range results {
+        path := repoRelativePath(repoPath, res.FilePath)
+        for _, msg := range res.Messages {
+            ruleID := msg.RuleID
+            if ruleID == "" {
+                ruleID = "parse-error"
+            }
+
+            severity := models.Warning
+            if msg.Severity == 2 || msg.Fatal {
+                severity = models.Error
+            }
+
+            issue := models.CodeIssue{
+                Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(msg.Message, 60)),
+                Description: msg.Message,
+                File:        path,
+                Line:        msg.Line,
+                Column:      msg.Column,
+                Severity:    severity,
+                Type:        eslintIssueType(ruleID, msg.Fatal),
+                Tool:        "eslint",
+                RuleID:      ruleID,
+            }
+            if msg.Fix != nil {
+                issue.Fix = msg.Fix.Text
+            }
+            if !strings.Contains(ruleID, "/") && ruleID != "parse-error" {
+                issue.URL = "https://eslint.org/docs/latest/rules/" + ruleID
+            }
+            issues = append(issues, issue)
+        }
+    }
+
+    return issues, nil
+}
+
+func eslintIssueType(ruleID string, fatal bool) models.IssueType {
+    switch {
+    case fatal:
+        return models.Bug
+    case strings.HasPrefix(ruleID, "security/"), strings.Contains(ruleID, "no-eval"),
+        strings.Contains(ruleID, "no-implied-eval"), strings.Contains(ruleID, "no-new-func"):
+        return models.Security
+    case strings.HasPrefix(ruleID, "no-unused"), strings.HasPrefix(ruleID, "no-undef"),
+        strings.Contains(ruleID, "no-unsafe"), strings.Contains(ruleID, "no-floating-promises"),
+        strings.HasPrefix(ruleID, "react-hooks/"):
+        return models.Bug
+    case strings.HasPrefix(ruleID, "jsdoc/"):
+        return models.Documentation
+    default:
+        return models.CodeStyle
+    }
+}
+
+// tscDiagnostic matches tsc's non-pretty output:
+// src/app.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.
+var tscDiagnostic = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): (error|warning|message) (TS\d+): (.*)$`)
+
+// parseTSCOutput parses tsc diagnostics whose paths are relative to dir.
+func parseTSCOutput(output, dir string) []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, line := range strings.Split(output, "\n") {
+        m := tscDiagnostic.FindStringSubmatch(strings.TrimSpace(line))
+        if m == nil {
+            continue
+        }
+
+        lineNum, _ := strconv.Atoi(m[2])
+        colNum, _ := strconv.Atoi(m[3])
+
+        severity := models.Error
+        if m[4] != "error" {
+            severity = models.Warning
+        }
+
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", m[5], shortenText(m[6], 60)),
+            Description: m[6],
+            File:        filepath.ToSlash(filepath.Join(dir, m[1])),
+            Line:        lineNum,
+            Column:      colNum,
+            Severity:    severity,
+            Type:        models.Bug,
+            Tool:        "tsc",
+            RuleID:      m[5],
+        })
+    }
+    return issues
+}
+
+// groupByNearestFile groups repo-relative files by the closest ancestor
+// directory containing any of markers, falling back to the repository root.
+func groupByNearestFile(repoPath string, files []string, markers ...string) map[string][]string {
+    groups := make(map[string][]string)
+    for _, file := range files {
+        dir := filepath.Dir(file)
+        for {
+            if hasAnyFile(filepath.Join(repoPath, dir), markers) {
+                break
+            }
+            if dir == "." || dir == "/" {
+                dir = "."
+                break
+            }
+            dir = filepath.Dir(dir)
+        }
+        groups[dir] = append(groups[dir], file)
+    }
+    return groups
+}
+
+func hasAnyFile(dir string, names []string) bool {
+    for _, name := range names {
+        if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+            return true
+        }
+    }
+    return false
+}
+
+func relativeTo(dir, file string) string {
+    if rel, err := filepath.Rel(dir, file); err == nil {
+        return rel
+    }
+    return file
+}
+
+func displayDir(dir string) string {
+    if dir == "." {
+        return "repository root"
+    }
+    return dir
+}
+
+func sortedKeys(m map[string][]string) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    return keys
+}
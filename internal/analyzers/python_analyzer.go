@@ -1,10 +1,11 @@
 package analyzers
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -24,14 +25,19 @@ func NewPythonAnalyzer(tools []models.Tool) *PythonAnalyzer {
     }
 }
 
-// Analyze runs the analysis for Python files.
+// pythonConfigFiles are the project files Python tools read their settings
+// from; tools run from the directory holding the nearest one.
+var pythonConfigFiles = []string{"pyproject.toml", "setup.cfg", "tox.ini", ".flake8"}
+
+// Analyze runs the analysis for Python files. Tools only see the changed
+// files and run once per project so they respect that project's config.
 func (a *PythonAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
     var allIssues []models.CodeIssue
 
-    var pyFiles []models.FileToAnalyze
+    var pyFiles []string
     for _, file := range files {
         if strings.HasSuffix(file.Path, ".py") {
-            pyFiles = append(pyFiles, file)
+            pyFiles = append(pyFiles, file.Path)
         }
     }
 
@@ -41,6 +47,8 @@ func (a *PythonAnalyzer) Analyze(ctx context.Context, repoPath string, files []m
 
     utils.LogWithLocation(utils.Info, "Analyzing %d Python files", len(pyFiles))
 
+    groups := groupByNearestFile(repoPath, pyFiles, pythonConfigFiles...)
+
     for _, tool := range a.tools {
         if !tool.Enabled {
             continue
@@ -48,40 +56,71 @@ func (a *PythonAnalyzer) Analyze(ctx context.Context, repoPath string, files []m
 
         utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
 
-        switch tool.Name {
-        case "flake8":
-            issues, err := a.runFlake8(ctx, repoPath, tool)
+        var toolIssues []models.CodeIssue
+        for _, dir := range sortedKeys(groups) {
+            var issues []models.CodeIssue
+            var err error
+
+            switch tool.Name {
+            case "flake8":
+                issues, err = a.runFlake8(ctx, repoPath, dir, groups[dir], tool)
+            case "ruff":
+                issues, err = a.runRuff(ctx, repoPath, dir, groups[dir], tool)
+            case "mypy":
+                issues, err = a.runMypy(ctx, repoPath, dir, groups[dir], tool)
+            case "bandit":
+                issues, err = a.runBandit(ctx, repoPath, dir, groups[dir], tool)
+            case "pylint":
+                issues, err = a.runPylint(ctx, repoPath, dir, groups[dir], tool)
+            default:
+                utils.LogWithLocation(utils.Warn, "Unknown Python tool: %s", tool.Name)
+            }
+
             if err != nil {
-                utils.LogWithLocation(utils.Error, "Error running flake8: %v", err)
+                utils.LogWithLocation(utils.Error, "Error running %s in %s: %v", tool.Name, displayDir(dir), err)
                 continue
             }
-            allIssues = append(allIssues, issues...)
+            toolIssues = append(toolIssues, issues...)
         }
+
+        utils.LogWithLocation(utils.Info, "Found %d issues from %s", len(toolIssues), tool.Name)
+        allIssues = append(allIssues, toolIssues...)
     }
 
     return allIssues, nil
 }
 
-// runFlake8 executes the flake8 linter and parses its output.
-func (a *PythonAnalyzer) runFlake8(ctx context.Context, repoPath string, tool models.Tool) ([]models.CodeIssue, error) {
-    cmd := exec.CommandContext(ctx, tool.Command, tool.Args...)
-    cmd.Dir = repoPath
-
-    var stdout, stderr bytes.Buffer
-    cmd.Stdout = &stdout
-    cmd.Stderr = &stderr
-
-    // flake8 exits with a non-zero status code if it finds issues, so we don't treat that as a fatal error.
-    _ = cmd.Run()
+// pythonToolArgs returns the configured args without any "." target, which
+// older configs used to lint the whole repository, followed by the files
+// relative to the project directory.
+func pythonToolArgs(tool models.Tool, extra []string, dir string, files []string) []string {
+    var args []string
+    for _, arg := range tool.Args {
+        if arg != "." {
+            args = append(args, arg)
+        }
+    }
+    args = append(args, extra...)
+    for _, file := range files {
+        args = append(args, relativeTo(dir, file))
+    }
+    return args
+}
 
-    if stderr.Len() > 0 {
-        utils.LogWithLocation(utils.Warn, "flake8 stderr: %s", stderr.String())
+// runFlake8 executes the flake8 linter and parses its output.
+func (a *PythonAnalyzer) runFlake8(ctx context.Context, repoPath, dir string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, pythonToolArgs(tool, nil, dir, files))
+    if err != nil {
+        return nil, err
     }
 
-    output := stdout.String()
     utils.LogWithLocation(utils.Debug, "flake8 output: %s", output)
 
-    return a.parseFlake8Output(output)
+    issues, err := a.parseFlake8Output(string(output))
+    for i := range issues {
+        issues[i].File = filepath.ToSlash(filepath.Join(dir, issues[i].File))
+    }
+    return issues, err
 }
 
 // parseFlake8Output converts flake8's default output into a slice of CodeIssue.
@@ -114,8 +153,8 @@ func (a *PythonAnalyzer) parseFlake8Output(output string) ([]models.CodeIssue, e
             Column:      colNum,
             Tool:        "flake8",
             RuleID:      ruleID,
-            Severity:    a.mapSeverity(ruleID),
-            Type:        a.mapIssueType(ruleID),
+            Severity:    pythonRuleSeverity(ruleID),
+            Type:        pythonRuleType(ruleID),
         }
         issues = append(issues, issue)
     }
@@ -132,32 +171,387 @@ func (a *PythonAnalyzer) createTitle(ruleID, message string) string {
     return fmt.Sprintf("%s: %s", title, message)
 }
 
-func (a *PythonAnalyzer) mapSeverity(ruleID string) models.IssueSeverity {
+// runRuff runs ruff with JSON output, keeping any available autofix.
+func (a *PythonAnalyzer) runRuff(ctx context.Context, repoPath, dir string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var extra []string
+    if !containsArg(tool.Args, "check") {
+        extra = append(extra, "check")
+    }
+    if !containsArg(tool.Args, "--output-format") {
+        extra = append(extra, "--output-format=json")
+    }
+
+    output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, pythonToolArgs(tool, extra, dir, files))
+    if err != nil {
+        return nil, err
+    }
+    return parseRuffJSON(output, repoPath)
+}
+
+// parseRuffJSON converts ruff's JSON output into CodeIssues.
+func parseRuffJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var results []struct {
+        Code     string `json:"code"`
+        Message  string `json:"message"`
+        Filename string `json:"filename"`
+        URL      string `json:"url"`
+        Location struct {
+            Row    int `json:"row"`
+            Column int `json:"column"`
+        } `json:"location"`
+        EndLocation struct {
+            Row int `json:"row"`
+        } `json:"end_location"`
+        Fix *struct {
+            Message string `json:"message"`
+            Edits   []struct {
+                Content string `json:"content"`
+            } `json:"edits"`
+        } `json:"fix"`
+    }
+    if err := json.Unmarshal(data, &results); err != nil {
+        return nil, fmt.Errorf("failed to parse ruff output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, r := range results {
+        // Syntax errors are reported without a rule code.
+        ruleID := r.Code
+        if ruleID == "" {
+            ruleID = "syntax-error"
+        }
+
+        issue := models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(r.Message, 60)),
+            Description: r.Message,
+            File:        repoRelativePath(repoPath, r.Filename),
+            Line:        r.Location.Row,
+            Column:      r.Location.Column,
+            EndLine:     r.EndLocation.Row,
+            Severity:    pythonRuleSeverity(ruleID),
+            Type:        pythonRuleType(ruleID),
+            Tool:        "ruff",
+            RuleID:      ruleID,
+            URL:         r.URL,
+        }
+        if r.Fix != nil {
+            var edits []string
+            for _, e := range r.Fix.Edits {
+                edits = append(edits, e.Content)
+            }
+            issue.Fix = strings.Join(edits, "\n")
+            if issue.Fix == "" {
+                issue.Fix = r.Fix.Message
+            }
+        }
+        issues = append(issues, issue)
+    }
+
+    return issues, nil
+}
+
+// pythonRuleSeverity maps flake8 and ruff rule codes to a severity. Ruff
+// reimplements pyflakes (F), pycodestyle (E, W), mccabe (C9) and the flake8
+// plugins under the same codes, so both tools share one mapping and
+// switching between them doesn't change severities or gate results.
+func pythonRuleSeverity(ruleID string) models.IssueSeverity {
     switch {
-    case strings.HasPrefix(ruleID, "F"): // PyFlakes (errors)
-        return models.Error
-    case strings.HasPrefix(ruleID, "E"): // pycodestyle (errors)
+    case ruleID == "syntax-error", ruffLinter(ruleID, "F"), ruffLinter(ruleID, "E"), ruffLinter(ruleID, "PLE"):
         return models.Error
-    case strings.HasPrefix(ruleID, "W"): // pycodestyle (warnings)
-        return models.Warning
-    case strings.HasPrefix(ruleID, "C"): // mccabe (complexity)
+    case ruffLinter(ruleID, "W"), ruffLinter(ruleID, "C"), ruffLinter(ruleID, "S"), ruffLinter(ruleID, "B"),
+        ruffLinter(ruleID, "PLC"), ruffLinter(ruleID, "PLR"), ruffLinter(ruleID, "PLW"):
         return models.Warning
     default:
         return models.Info
     }
 }
 
-func (a *PythonAnalyzer) mapIssueType(ruleID string) models.IssueType {
+// pythonRuleType maps flake8 and ruff rule codes to an issue type.
+func pythonRuleType(ruleID string) models.IssueType {
     switch {
-    case strings.HasPrefix(ruleID, "F"):
+    case ruffLinter(ruleID, "S"):
+        return models.Security
+    case ruffLinter(ruleID, "PERF"):
+        return models.Performance
+    case ruleID == "syntax-error", ruffLinter(ruleID, "F"), ruffLinter(ruleID, "B"), ruffLinter(ruleID, "PLE"):
         return models.Bug
-    case strings.HasPrefix(ruleID, "E"):
-        return models.CodeStyle
-    case strings.HasPrefix(ruleID, "W"):
+    case ruffLinter(ruleID, "D"):
+        return models.Documentation
+    case ruffLinter(ruleID, "C9"), ruffLinter(ruleID, "PLR"), ruffLinter(ruleID, "SIM"):
+        return models.Maintainability
+    default:
         return models.CodeStyle
-    case strings.HasPrefix(ruleID, "C"):
+    }
+}
+
+// ruffLinter reports whether ruleID is a code of the linter with prefix.
+// Prefixes overlap (S and SIM, F and FURB, D and DTZ), so the prefix must
+// be followed by the rule number.
+func ruffLinter(ruleID, prefix string) bool {
+    number, ok := strings.CutPrefix(ruleID, prefix)
+    return ok && number != "" && number[0] >= '0' && number[0] <= '9'
+}
+
+// runMypy runs mypy with its JSON output format (mypy 1.11+).
+func (a *PythonAnalyzer) runMypy(ctx context.Context, repoPath, dir string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var extra []string
+    if !containsArg(tool.Args, "--output") && !containsArg(tool.Args, "-O") {
+        extra = append(extra, "--output", "json")
+    }
+
+    output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, pythonToolArgs(tool, extra, dir, files))
+    if err != nil {
+        return nil, err
+    }
+    return parseMypyJSON(output, dir, files)
+}
+
+// parseMypyJSON parses mypy's line-delimited JSON diagnostics. Notes are
+// folded into the preceding error, as mypy emits them as follow-ups. mypy
+// also checks the modules the files import, so diagnostics outside files
+// are dropped.
+func parseMypyJSON(data []byte, dir string, files []string) ([]models.CodeIssue, error) {
+    changed := make(map[string]bool, len(files))
+    for _, file := range files {
+        changed[filepath.ToSlash(file)] = true
+    }
+
+    var issues []models.CodeIssue
+    kept := false
+
+    for _, line := range strings.Split(string(data), "\n") {
+        line = strings.TrimSpace(line)
+        if !strings.HasPrefix(line, "{") {
+            continue
+        }
+
+        var d struct {
+            File     string  `json:"file"`
+            Line     int     `json:"line"`
+            Column   int     `json:"column"`
+            Message  string  `json:"message"`
+            Hint     *string `json:"hint"`
+            Code     *string `json:"code"`
+            Severity string  `json:"severity"`
+        }
+        if err := json.Unmarshal([]byte(line), &d); err != nil {
+            return nil, fmt.Errorf("failed to parse mypy output: %v", err)
+        }
+
+        if d.Severity == "note" {
+            if n := len(issues); kept && issues[n-1].Line == d.Line {
+                issues[n-1].Description += "\n" + d.Message
+            }
+            continue
+        }
+
+        file := filepath.ToSlash(filepath.Join(dir, d.File))
+        kept = changed[file]
+        if !kept {
+            continue
+        }
+
+        ruleID := "mypy"
+        if d.Code != nil && *d.Code != "" {
+            ruleID = *d.Code
+        }
+
+        description := d.Message
+        if d.Hint != nil && *d.Hint != "" {
+            description += "\n" + *d.Hint
+        }
+
+        severity := models.Error
+        if d.Severity == "warning" {
+            severity = models.Warning
+        }
+
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(d.Message, 60)),
+            Description: description,
+            File:        file,
+            Line:        d.Line,
+            Column:      d.Column + 1,
+            Severity:    severity,
+            Type:        models.Bug,
+            Tool:        "mypy",
+            RuleID:      ruleID,
+            URL:         "https://mypy.readthedocs.io/en/stable/error_code_list.html",
+        })
+    }
+
+    return issues, nil
+}
+
+// runBandit runs bandit with JSON output. bandit only reads pyproject.toml
+// when pointed at it, so it is passed explicitly when it has a [tool.bandit] table.
+func (a *PythonAnalyzer) runBandit(ctx context.Context, repoPath, dir string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var extra []string
+    if !containsArg(tool.Args, "-f") && !containsArg(tool.Args, "--format") {
+        extra = append(extra, "-f", "json")
+    }
+    if !containsArg(tool.Args, "-c") && !containsArg(tool.Args, "--configfile") {
+        if content, err := os.ReadFile(filepath.Join(repoPath, dir, "pyproject.toml")); err == nil &&
+            strings.Contains(string(content), "[tool.bandit") {
+            extra = append(extra, "-c", "pyproject.toml")
+        }
+    }
+
+    output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, pythonToolArgs(tool, extra, dir, files))
+    if err != nil {
+        return nil, err
+    }
+    return parseBanditJSON(output, dir)
+}
+
+// parseBanditJSON converts bandit's JSON report into security CodeIssues.
+func parseBanditJSON(data []byte, dir string) ([]models.CodeIssue, error) {
+    var report struct {
+        Results []struct {
+            Filename   string `json:"filename"`
+            LineNumber int    `json:"line_number"`
+            ColOffset  int    `json:"col_offset"`
+            Severity   string `json:"issue_severity"`
+            Confidence string `json:"issue_confidence"`
+            Text       string `json:"issue_text"`
+            TestID     string `json:"test_id"`
+            TestName   string `json:"test_name"`
+            MoreInfo   string `json:"more_info"`
+            Code       string `json:"code"`
+            CWE        struct {
+                ID int `json:"id"`
+            } `json:"issue_cwe"`
+        } `json:"results"`
+    }
+    if err := json.Unmarshal(trimToJSONObject(data), &report); err != nil {
+        return nil, fmt.Errorf("failed to parse bandit output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, r := range report.Results {
+        issue := models.CodeIssue{
+            Title:       fmt.Sprintf("%s (%s): %s", r.TestID, r.TestName, shortenText(r.Text, 60)),
+            Description: r.Text,
+            File:        filepath.ToSlash(filepath.Join(dir, strings.TrimPrefix(r.Filename, "./"))),
+            Line:        r.LineNumber,
+            Column:      r.ColOffset + 1,
+            Severity:    banditSeverity(r.Severity, r.Confidence),
+            Type:        models.Security,
+            Tool:        "bandit",
+            RuleID:      r.TestID,
+            Rule:        r.TestName,
+            URL:         r.MoreInfo,
+            Code:        r.Code,
+            Confidence:  strings.ToUpper(r.Confidence),
+        }
+        if r.CWE.ID != 0 {
+            issue.Metadata = map[string]string{"cwe": fmt.Sprintf("CWE-%d", r.CWE.ID)}
+        }
+        issues = append(issues, issue)
+    }
+
+    return issues, nil
+}
+
+// banditSeverity combines bandit's severity and confidence: a high severity
+// finding is only critical when bandit is also confident about it.
+func banditSeverity(severity, confidence string) models.IssueSeverity {
+    switch strings.ToUpper(severity) {
+    case "HIGH":
+        if strings.ToUpper(confidence) == "HIGH" {
+            return models.Critical
+        }
+        return models.Error
+    case "MEDIUM":
+        return models.Warning
+    case "LOW":
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
+
+// runPylint runs pylint with JSON output.
+func (a *PythonAnalyzer) runPylint(ctx context.Context, repoPath, dir string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var extra []string
+    if !containsArg(tool.Args, "--output-format") && !containsArg(tool.Args, "-f") {
+        extra = append(extra, "--output-format=json")
+    }
+
+    output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, pythonToolArgs(tool, extra, dir, files))
+    if err != nil {
+        return nil, err
+    }
+    return parsePylintJSON(output, dir)
+}
+
+// parsePylintJSON converts pylint's JSON output into CodeIssues.
+func parsePylintJSON(data []byte, dir string) ([]models.CodeIssue, error) {
+    if len(strings.TrimSpace(string(data))) == 0 {
+        return nil, nil
+    }
+
+    var results []struct {
+        Type      string `json:"type"`
+        Line      int    `json:"line"`
+        Column    int    `json:"column"`
+        Path      string `json:"path"`
+        Symbol    string `json:"symbol"`
+        Message   string `json:"message"`
+        MessageID string `json:"message-id"`
+    }
+    if err := json.Unmarshal(data, &results); err != nil {
+        return nil, fmt.Errorf("failed to parse pylint output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, r := range results {
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s (%s): %s", r.MessageID, r.Symbol, shortenText(r.Message, 60)),
+            Description: r.Message,
+            File:        filepath.ToSlash(filepath.Join(dir, r.Path)),
+            Line:        r.Line,
+            Column:      r.Column + 1,
+            Severity:    pylintSeverity(r.Type),
+            Type:        pylintIssueType(r.Type),
+            Tool:        "pylint",
+            RuleID:      r.MessageID,
+            Rule:        r.Symbol,
+            URL:         fmt.Sprintf("https://pylint.readthedocs.io/en/stable/user_guide/messages/%s/%s.html", r.Type, r.Symbol),
+        })
+    }
+
+    return issues, nil
+}
+
+func pylintSeverity(msgType string) models.IssueSeverity {
+    switch msgType {
+    case "fatal", "error":
+        return models.Error
+    case "warning":
+        return models.Warning
+    case "refactor":
+        return models.Info
+    default:
+        return models.Hint
+    }
+}
+
+func pylintIssueType(msgType string) models.IssueType {
+    switch msgType {
+    case "fatal", "error", "warning":
+        return models.Bug
+    case "refactor":
         return models.Maintainability
     default:
         return models.CodeStyle
     }
-}
\ No newline at end of file
+}
+
+// trimToJSONObject drops any log lines printed before a JSON object.
+func trimToJSONObject(data []byte) []byte {
+    if idx := strings.IndexByte(string(data), '{'); idx > 0 {
+        return data[idx:]
+    }
+    return data
+}
@@ -0,0 +1,81 @@
+package analyzers
+
+import (
+	"io"
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+func TestPythonRuleSeverity(t *testing.T) {
+    tests := []struct {
+        ruleID   string
+        severity models.IssueSeverity
+        typ      models.IssueType
+    }{
+        {"syntax-error", models.Error, models.Bug},
+        {"F401", models.Error, models.Bug},
+        {"E501", models.Error, models.CodeStyle},
+        {"E999", models.Error, models.CodeStyle},
+        {"W291", models.Warning, models.CodeStyle},
+        {"C901", models.Warning, models.Maintainability},
+        {"C408", models.Warning, models.CodeStyle},
+        {"S105", models.Warning, models.Security},
+        {"B006", models.Warning, models.Bug},
+        {"PLE0101", models.Error, models.Bug},
+        {"PLR0913", models.Warning, models.Maintainability},
+        {"SIM108", models.Info, models.Maintainability},
+        {"D103", models.Info, models.Documentation},
+        {"PERF401", models.Info, models.Performance},
+        {"ERA001", models.Info, models.CodeStyle},
+        {"FURB118", models.Info, models.CodeStyle},
+    }
+    for _, tt := range tests {
+        if got := pythonRuleSeverity(tt.ruleID); got != tt.severity {
+            t.Errorf("pythonRuleSeverity(%s) = %s, want %s", tt.ruleID, got, tt.severity)
+        }
+        if got := pythonRuleType(tt.ruleID); got != tt.typ {
+            t.Errorf("pythonRuleType(%s) = %s, want %s", tt.ruleID, got, tt.typ)
+        }
+    }
+}
+
+// TestFlake8AndRuffAgree checks that the same findings get the same severity
+// and type whichever linter reports them.
+func TestFlake8AndRuffAgree(t *testing.T) {
+    utils.InitLogger(io.Discard, io.Discard, io.Discard, io.Discard)
+
+    flake8, err := NewPythonAnalyzer(nil).parseFlake8Output(
+        "app.py:1:1: F401 'os' imported but unused\n" +
+            "app.py:2:80: E501 line too long (120 > 79 characters)\n" +
+            "app.py:3:1: E302 expected 2 blank lines, found 1\n" +
+            "app.py:4:10: W291 trailing whitespace\n" +
+            "app.py:5:1: C901 'run' is too complex (12)\n")
+    if err != nil {
+        t.Fatalf("parseFlake8Output failed: %v", err)
+    }
+    ruff, err := parseRuffJSON([]byte(`[
+        {"code": "F401", "message": "os imported but unused", "filename": "/repo/app.py", "location": {"row": 1, "column": 1}},
+        {"code": "E501", "message": "Line too long (120 > 88)", "filename": "/repo/app.py", "location": {"row": 2, "column": 89}},
+        {"code": "E302", "message": "Expected 2 blank lines, found 1", "filename": "/repo/app.py", "location": {"row": 3, "column": 1}},
+        {"code": "W291", "message": "Trailing whitespace", "filename": "/repo/app.py", "location": {"row": 4, "column": 10}},
+        {"code": "C901", "message": "run is too complex (12 > 10)", "filename": "/repo/app.py", "location": {"row": 5, "column": 1}}
+    ]`), "/repo")
+    if err != nil {
+        t.Fatalf("parseRuffJSON failed: %v", err)
+    }
+
+    if len(flake8) != len(ruff) {
+        t.Fatalf("flake8 reported %d issues, ruff %d", len(flake8), len(ruff))
+    }
+    for i := range flake8 {
+        if flake8[i].RuleID != ruff[i].RuleID {
+            t.Fatalf("issue %d: flake8 %s, ruff %s", i, flake8[i].RuleID, ruff[i].RuleID)
+        }
+        if flake8[i].Severity != ruff[i].Severity || flake8[i].Type != ruff[i].Type {
+            t.Errorf("%s: flake8 %s/%s, ruff %s/%s", flake8[i].RuleID,
+                flake8[i].Severity, flake8[i].Type, ruff[i].Severity, ruff[i].Type)
+        }
+    }
+}
@@ -0,0 +1,272 @@
+package analyzers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// RustAnalyzer analyzes Rust code using cargo clippy.
+type RustAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewRustAnalyzer creates a new RustAnalyzer.
+func NewRustAnalyzer(tools []models.Tool) *RustAnalyzer {
+    return &RustAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured tools for every crate that owns a changed .rs file.
+func (a *RustAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var rsFiles []string
+    for _, file := range files {
+        if strings.HasSuffix(file.Path, ".rs") {
+            rsFiles = append(rsFiles, file.Path)
+        }
+    }
+
+    if len(rsFiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d Rust files", len(rsFiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        switch tool.Name {
+        case "clippy":
+            issues, err := a.runClippy(ctx, repoPath, rsFiles, tool)
+            if err != nil {
+                utils.LogWithLocation(utils.Error, "Error running clippy: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "Found %d issues from clippy", len(issues))
+            allIssues = append(allIssues, issues...)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown Rust tool: %s", tool.Name)
+        }
+    }
+
+    return allIssues, nil
+}
+
+// runClippy runs clippy once per crate (nearest Cargo.toml) and keeps the
+// diagnostics whose primary span falls in a changed file.
+func (a *RustAnalyzer) runClippy(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var issues []models.CodeIssue
+
+    changed := make(map[string]bool)
+    for _, file := range files {
+        changed[file] = true
+    }
+
+    seen := make(map[string]bool)
+    groups := groupByNearestFile(repoPath, files, "Cargo.toml")
+    for _, dir := range sortedKeys(groups) {
+        args := append([]string{}, tool.Args...)
+        if len(args) == 0 {
+            args = []string{"clippy"}
+        }
+        if !containsArg(args, "--message-format") {
+            args = append(args, "--message-format=json")
+        }
+
+        output, err := runToolCommand(ctx, filepath.Join(repoPath, dir), tool.Command, args)
+        if err != nil {
+            return nil, err
+        }
+
+        crateIssues, err := parseClippyJSON(output, dir, files)
+        if err != nil {
+            return nil, fmt.Errorf("%s: %v", displayDir(dir), err)
+        }
+        for _, issue := range crateIssues {
+            // Crates in one workspace share diagnostics, so skip repeats.
+            key := fmt.Sprintf("%s:%d:%d:%s", issue.File, issue.Line, issue.Column, issue.RuleID)
+            if !changed[issue.File] || seen[key] {
+                continue
+            }
+            seen[key] = true
+            issues = append(issues, issue)
+        }
+    }
+
+    return issues, nil
+}
+
+type clippySpan struct {
+    FileName             string  `json:"file_name"`
+    LineStart            int     `json:"line_start"`
+    ColumnStart          int     `json:"column_start"`
+    IsPrimary            bool    `json:"is_primary"`
+    Text                 []struct {
+        Text string `json:"text"`
+    } `json:"text"`
+    SuggestedReplacement *string `json:"suggested_replacement"`
+}
+
+type clippyDiagnostic struct {
+    Message string `json:"message"`
+    Code    *struct {
+        Code string `json:"code"`
+    } `json:"code"`
+    Level    string             `json:"level"`
+    Spans    []clippySpan       `json:"spans"`
+    Children []clippyDiagnostic `json:"children"`
+    Rendered string             `json:"rendered"`
+}
+
+// parseClippyJSON parses cargo's line-delimited JSON messages. Span paths are
+// relative to the workspace root, which may sit above dir, so they are
+// resolved against the changed files.
+func parseClippyJSON(data []byte, dir string, files []string) ([]models.CodeIssue, error) {
+    var issues []models.CodeIssue
+
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+    for scanner.Scan() {
+        line := bytes.TrimSpace(scanner.Bytes())
+        if len(line) == 0 || line[0] != '{' {
+            continue
+        }
+
+        var msg struct {
+            Reason  string           `json:"reason"`
+            Message clippyDiagnostic `json:"message"`
+        }
+        if err := json.Unmarshal(line, &msg); err != nil {
+            utils.LogWithLocation(utils.Debug, "Skipping unparsable cargo message: %v", err)
+            continue
+        }
+        if msg.Reason != "compiler-message" {
+            continue
+        }
+
+        diag := msg.Message
+        if diag.Level != "error" && diag.Level != "warning" {
+            continue
+        }
+
+        var primary *clippySpan
+        for i := range diag.Spans {
+            if diag.Spans[i].IsPrimary {
+                primary = &diag.Spans[i]
+                break
+            }
+        }
+        if primary == nil {
+            continue
+        }
+
+        ruleID := ""
+        if diag.Code != nil {
+            ruleID = diag.Code.Code
+        }
+
+        title := diag.Message
+        if ruleID != "" {
+            title = fmt.Sprintf("%s: %s", ruleID, shortenText(diag.Message, 60))
+        }
+
+        description := diag.Message
+        for _, child := range diag.Children {
+            if child.Message != "" && len(child.Spans) == 0 {
+                description += "\n" + child.Level + ": " + child.Message
+            }
+        }
+
+        issue := models.CodeIssue{
+            Title:       title,
+            Description: description,
+            File:        resolveCargoPath(primary.FileName, dir, files),
+            Line:        primary.LineStart,
+            Column:      primary.ColumnStart,
+            Severity:    clippySeverity(diag.Level),
+            Type:        clippyIssueType(ruleID),
+            Tool:        "clippy",
+            RuleID:      ruleID,
+            Fix:         clippySuggestion(diag),
+        }
+        if len(primary.Text) > 0 {
+            issue.Code = primary.Text[0].Text
+        }
+        if strings.HasPrefix(ruleID, "clippy::") {
+            issue.URL = "https://rust-lang.github.io/rust-clippy/master/index.html#" + strings.TrimPrefix(ruleID, "clippy::")
+        }
+        issues = append(issues, issue)
+    }
+
+    return issues, scanner.Err()
+}
+
+func resolveCargoPath(name, dir string, files []string) string {
+    joined := filepath.ToSlash(filepath.Join(dir, name))
+    for _, file := range files {
+        if file == joined {
+            return file
+        }
+    }
+    return matchChangedFile(name, files)
+}
+
+// clippySuggestion collects suggested replacements from the diagnostic and
+// its "help" children.
+func clippySuggestion(diag clippyDiagnostic) string {
+    var suggestions []string
+    collect := func(spans []clippySpan) {
+        for _, span := range spans {
+            if span.SuggestedReplacement != nil {
+                suggestions = append(suggestions, *span.SuggestedReplacement)
+            }
+        }
+    }
+    collect(diag.Spans)
+    for _, child := range diag.Children {
+        collect(child.Spans)
+    }
+    return strings.Join(suggestions, "\n")
+}
+
+func clippySeverity(level string) models.IssueSeverity {
+    if level == "error" {
+        return models.Error
+    }
+    return models.Warning
+}
+
+func clippyIssueType(ruleID string) models.IssueType {
+    switch {
+    case ruleID == "", strings.HasPrefix(ruleID, "E0"):
+        return models.Bug
+    case strings.Contains(ruleID, "unsafe"), strings.Contains(ruleID, "transmute"):
+        return models.Security
+    case strings.Contains(ruleID, "perf"), strings.Contains(ruleID, "clone"),
+        strings.Contains(ruleID, "alloc"), strings.Contains(ruleID, "large_"):
+        return models.Performance
+    case strings.Contains(ruleID, "unwrap"), strings.Contains(ruleID, "panic"),
+        strings.Contains(ruleID, "overflow"), strings.HasPrefix(ruleID, "unused_must_use"):
+        return models.Bug
+    case strings.Contains(ruleID, "doc"):
+        return models.Documentation
+    case strings.HasPrefix(ruleID, "clippy::"):
+        return models.Maintainability
+    default:
+        return models.CodeStyle
+    }
+}
@@ -0,0 +1,142 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/sarif"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// filesPlaceholder in a SARIF tool's args expands to the changed files.
+const filesPlaceholder = "{files}"
+
+// SARIFAnalyzer imports findings from any tool that emits SARIF 2.1.0. A
+// tool with a command is run and its stdout parsed; a tool without one reads
+// the SARIF files (or glob patterns) listed in its args, relative to the
+// repository, such as reports produced by an earlier CI step.
+type SARIFAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewSARIFAnalyzer creates a new SARIFAnalyzer.
+func NewSARIFAnalyzer(tools []models.Tool) *SARIFAnalyzer {
+    return &SARIFAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze collects the configured SARIF reports and keeps the findings in
+// the changed files.
+func (a *SARIFAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    paths := make([]string, 0, len(files))
+    changed := make(map[string]bool, len(files))
+    for _, file := range files {
+        paths = append(paths, file.Path)
+        changed[file.Path] = true
+    }
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        var issues []models.CodeIssue
+        var err error
+        if tool.Command != "" {
+            issues, err = a.runTool(ctx, repoPath, paths, tool)
+        } else {
+            issues, err = a.readReports(repoPath, paths, tool)
+        }
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Error importing SARIF from %s: %v", tool.Name, err)
+            continue
+        }
+
+        kept := 0
+        for _, issue := range issues {
+            if !changed[issue.File] {
+                continue
+            }
+            allIssues = append(allIssues, issue)
+            kept++
+        }
+        utils.LogWithLocation(utils.Info, "Found %d issues from %s (%d outside the changed files skipped)", kept, tool.Name, len(issues)-kept)
+    }
+
+    return allIssues, nil
+}
+
+func (a *SARIFAnalyzer) runTool(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    var args []string
+    for _, arg := range tool.Args {
+        if arg == filesPlaceholder {
+            args = append(args, files...)
+            continue
+        }
+        args = append(args, arg)
+    }
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseSARIF(output, repoPath, tool.Name, files)
+}
+
+func (a *SARIFAnalyzer) readReports(repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    if len(tool.Args) == 0 {
+        return nil, fmt.Errorf("no command or SARIF report paths configured")
+    }
+
+    var issues []models.CodeIssue
+    for _, pattern := range tool.Args {
+        if !filepath.IsAbs(pattern) {
+            pattern = filepath.Join(repoPath, pattern)
+        }
+        matches, err := filepath.Glob(pattern)
+        if err != nil {
+            return nil, fmt.Errorf("invalid report pattern %q: %v", pattern, err)
+        }
+        if len(matches) == 0 {
+            utils.LogWithLocation(utils.Warn, "No SARIF reports match %s", pattern)
+        }
+
+        for _, path := range matches {
+            data, err := os.ReadFile(path)
+            if err != nil {
+                return nil, fmt.Errorf("failed to read SARIF report: %v", err)
+            }
+            found, err := parseSARIF(data, repoPath, tool.Name, files)
+            if err != nil {
+                return nil, fmt.Errorf("%s: %v", path, err)
+            }
+            issues = append(issues, found...)
+        }
+    }
+    return issues, nil
+}
+
+// parseSARIF converts a SARIF log into CodeIssues attributed to tool, with
+// paths resolved against the files under analysis.
+func parseSARIF(data []byte, repoPath, tool string, files []string) ([]models.CodeIssue, error) {
+    log, err := sarif.Parse(data)
+    if err != nil {
+        return nil, err
+    }
+
+    issues := log.Issues(tool)
+    for i := range issues {
+        if issues[i].File != "" {
+            issues[i].File = matchChangedFile(repoRelativePath(repoPath, issues[i].File), files)
+        }
+    }
+    return issues, nil
+}
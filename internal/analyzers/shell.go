@@ -0,0 +1,153 @@
+package analyzers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// ShellAnalyzer analyzes shell scripts using shellcheck.
+type ShellAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewShellAnalyzer creates a new ShellAnalyzer.
+func NewShellAnalyzer(tools []models.Tool) *ShellAnalyzer {
+    return &ShellAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured tools over the changed shell scripts.
+func (a *ShellAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var shFiles []string
+    for _, file := range files {
+        if models.DetectLanguageFromFile(file.Path) == "shell" {
+            shFiles = append(shFiles, file.Path)
+        }
+    }
+
+    if len(shFiles) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d shell scripts", len(shFiles))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        switch tool.Name {
+        case "shellcheck":
+            issues, err := a.runShellcheck(ctx, repoPath, shFiles, tool)
+            if err != nil {
+                utils.LogWithLocation(utils.Error, "Error running shellcheck: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "Found %d issues from shellcheck", len(issues))
+            allIssues = append(allIssues, issues...)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown shell tool: %s", tool.Name)
+        }
+    }
+
+    return allIssues, nil
+}
+
+func (a *ShellAnalyzer) runShellcheck(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "-f") && !containsArg(args, "--format") {
+        args = append(args, "-f", "json1")
+    }
+    args = append(args, files...)
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseShellcheckJSON(output, repoPath)
+}
+
+type shellcheckComment struct {
+    File    string `json:"file"`
+    Line    int    `json:"line"`
+    Column  int    `json:"column"`
+    Level   string `json:"level"`
+    Code    int    `json:"code"`
+    Message string `json:"message"`
+    Fix     *struct {
+        Replacements []struct {
+            Replacement string `json:"replacement"`
+        } `json:"replacements"`
+    } `json:"fix"`
+}
+
+// parseShellcheckJSON accepts both the "json" (bare array) and "json1"
+// (object with a comments array) output formats.
+func parseShellcheckJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    var comments []shellcheckComment
+
+    trimmed := bytes.TrimSpace(data)
+    if len(trimmed) == 0 {
+        return nil, nil
+    }
+    if trimmed[0] == '[' {
+        if err := json.Unmarshal(trimmed, &comments); err != nil {
+            return nil, fmt.Errorf("failed to parse shellcheck output: %v", err)
+        }
+    } else {
+        var report struct {
+            Comments []shellcheckComment `json:"comments"`
+        }
+        if err := json.Unmarshal(trimmed, &report); err != nil {
+            return nil, fmt.Errorf("failed to parse shellcheck output: %v", err)
+        }
+        comments = report.Comments
+    }
+
+    var issues []models.CodeIssue
+    for _, c := range comments {
+        ruleID := fmt.Sprintf("SC%d", c.Code)
+        issue := models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", ruleID, shortenText(c.Message, 60)),
+            Description: c.Message,
+            File:        repoRelativePath(repoPath, c.File),
+            Line:        c.Line,
+            Column:      c.Column,
+            Severity:    lintLevelSeverity(c.Level),
+            Type:        shellcheckIssueType(c.Level),
+            Tool:        "shellcheck",
+            RuleID:      ruleID,
+            URL:         "https://www.shellcheck.net/wiki/" + ruleID,
+        }
+        if c.Fix != nil {
+            var parts []string
+            for _, r := range c.Fix.Replacements {
+                parts = append(parts, r.Replacement)
+            }
+            issue.Fix = strings.Join(parts, "\n")
+        }
+        issues = append(issues, issue)
+    }
+
+    return issues, nil
+}
+
+func shellcheckIssueType(level string) models.IssueType {
+    switch strings.ToLower(level) {
+    case "error", "warning":
+        return models.Bug
+    default:
+        return models.CodeStyle
+    }
+}
@@ -0,0 +1,170 @@
+package analyzers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// suppressionPattern matches gollora:ignore and gollora:ignore-file directives
+// in //, /* and # comments, e.g.
+//
+//	// gollora:ignore errcheck -- closed by the caller
+//	# gollora:ignore bandit:B105 -- test fixture
+//	// gollora:ignore-file golangci-lint -- generated code
+var suppressionPattern = regexp.MustCompile(`(?://|/\*|#)\s*gollora:ignore(-file)?\b(.*)$`)
+
+// suppressionDirective is a parsed gollora:ignore comment.
+type suppressionDirective struct {
+    fileLevel     bool
+    line          int
+    text          string
+    targets       []string
+    justification string
+}
+
+// matches reports whether the directive covers issue. A directive without
+// targets covers everything; otherwise each target may name a rule ID, a
+// tool, or "tool:rule".
+func (d suppressionDirective) matches(issue models.CodeIssue) bool {
+    if len(d.targets) == 0 {
+        return true
+    }
+    for _, target := range d.targets {
+        if tool, rule, ok := strings.Cut(target, ":"); ok {
+            if strings.EqualFold(tool, issue.Tool) && issueHasRule(issue, rule) {
+                return true
+            }
+            continue
+        }
+        if strings.EqualFold(target, issue.Tool) || issueHasRule(issue, target) {
+            return true
+        }
+    }
+    return false
+}
+
+func issueHasRule(issue models.CodeIssue, rule string) bool {
+    return rule != "" && (strings.EqualFold(rule, issue.RuleID) || strings.EqualFold(rule, issue.Rule))
+}
+
+// commentStart returns the index of the first //, /* or # on the line that
+// isn't inside a string literal, or -1. A quote without a closing quote on
+// the same line, such as a Rust lifetime, is taken as plain text.
+func commentStart(line string) int {
+    for i := 0; i < len(line); i++ {
+        switch line[i] {
+        case '"', '\'', '`':
+            if end := closingQuote(line, i); end > 0 {
+                i = end
+            }
+        case '#':
+            return i
+        case '/':
+            if i+1 < len(line) && (line[i+1] == '/' || line[i+1] == '*') {
+                return i
+            }
+        }
+    }
+    return -1
+}
+
+// closingQuote returns the index of the quote closing the literal opened at
+// start, skipping backslash escapes, or -1 if the line has none.
+func closingQuote(line string, start int) int {
+    quote := line[start]
+    for i := start + 1; i < len(line); i++ {
+        switch line[i] {
+        case '\\':
+            if quote != '`' {
+                i++
+            }
+        case quote:
+            return i
+        }
+    }
+    return -1
+}
+
+// parseSuppressions extracts all directives from a file's content. Only
+// directives in comments count, not text in string literals.
+func parseSuppressions(content string) []suppressionDirective {
+    var directives []suppressionDirective
+    for i, line := range strings.Split(content, "\n") {
+        start := commentStart(line)
+        if start < 0 {
+            continue
+        }
+        m := suppressionPattern.FindStringSubmatch(line[start:])
+        if m == nil {
+            continue
+        }
+
+        rest := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[2]), "*/"))
+        targets, reason, _ := strings.Cut(rest, "--")
+
+        d := suppressionDirective{
+            fileLevel:     m[1] != "",
+            line:          i + 1,
+            text:          strings.TrimSpace(m[0]),
+            justification: strings.TrimSpace(reason),
+        }
+        for _, target := range strings.FieldsFunc(targets, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+            d.targets = append(d.targets, target)
+        }
+        directives = append(directives, d)
+    }
+    return directives
+}
+
+// ApplySuppressions marks issues covered by a gollora:ignore directive in the
+// analyzed files as suppressed. A line directive covers its own line and the
+// line after it; gollora:ignore-file covers the whole file. It returns the
+// number of issues suppressed.
+func ApplySuppressions(issues []models.CodeIssue, files []models.FileToAnalyze) int {
+    directivesByFile := make(map[string][]suppressionDirective)
+    for _, file := range files {
+        if !strings.Contains(file.Content, "gollora:ignore") {
+            continue
+        }
+        directivesByFile[file.Path] = parseSuppressions(file.Content)
+    }
+
+    if len(directivesByFile) == 0 {
+        return 0
+    }
+
+    suppressed := 0
+    for i := range issues {
+        issue := &issues[i]
+        if issue.Status == models.StatusSuppressed {
+            continue
+        }
+
+        for _, d := range directivesByFile[issue.File] {
+            if !d.fileLevel && issue.Line != d.line && issue.Line != d.line+1 {
+                continue
+            }
+            if !d.matches(*issue) {
+                continue
+            }
+
+            scope := "line"
+            if d.fileLevel {
+                scope = "file"
+            }
+            issue.Status = models.StatusSuppressed
+            issue.Suppression = &models.Suppression{
+                Scope:         scope,
+                Line:          d.line,
+                Directive:     d.text,
+                Justification: d.justification,
+            }
+            suppressed++
+            break
+        }
+    }
+
+    return suppressed
+}
@@ -0,0 +1,179 @@
+package analyzers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func TestParseSuppressions(t *testing.T) {
+    tests := []struct {
+        name string
+        line string
+        want []suppressionDirective
+    }{
+        {
+            name: "go comment with target and justification",
+            line: "    f.Close() // gollora:ignore errcheck -- closed by the caller",
+            want: []suppressionDirective{{
+                line:          1,
+                text:          "// gollora:ignore errcheck -- closed by the caller",
+                targets:       []string{"errcheck"},
+                justification: "closed by the caller",
+            }},
+        },
+        {
+            name: "hash comment with several targets",
+            line: "password = 'x'  # gollora:ignore bandit:B105, ruff:S105 -- test fixture",
+            want: []suppressionDirective{{
+                line:          1,
+                text:          "# gollora:ignore bandit:B105, ruff:S105 -- test fixture",
+                targets:       []string{"bandit:B105", "ruff:S105"},
+                justification: "test fixture",
+            }},
+        },
+        {
+            name: "block comment file directive",
+            line: "/* gollora:ignore-file golangci-lint */",
+            want: []suppressionDirective{{
+                fileLevel: true,
+                line:      1,
+                text:      "/* gollora:ignore-file golangci-lint */",
+                targets:   []string{"golangci-lint"},
+            }},
+        },
+        {
+            name: "bare directive",
+            line: "eval(input) # gollora:ignore",
+            want: []suppressionDirective{{line: 1, text: "# gollora:ignore"}},
+        },
+        {
+            name: "url in a string before the comment",
+            line: `fetch("http://example.com") // gollora:ignore no-unsafe-fetch`,
+            want: []suppressionDirective{{
+                line:    1,
+                text:    "// gollora:ignore no-unsafe-fetch",
+                targets: []string{"no-unsafe-fetch"},
+            }},
+        },
+        {
+            name: "double-quoted string",
+            line: `msg = "# gollora:ignore"`,
+        },
+        {
+            name: "single-quoted string",
+            line: `const hint = '// gollora:ignore errcheck';`,
+        },
+        {
+            name: "raw string",
+            line: "doc := `/* gollora:ignore-file */`",
+        },
+        {
+            name: "escaped quote in a string",
+            line: `s := "say \"hi\" # gollora:ignore"`,
+        },
+        {
+            name: "rust lifetime before the comment",
+            line: "fn name<'a>(s: &'a str) -> &'a str { s } // gollora:ignore clippy",
+            want: []suppressionDirective{{
+                line:    1,
+                text:    "// gollora:ignore clippy",
+                targets: []string{"clippy"},
+            }},
+        },
+        {
+            name: "not a directive",
+            line: "// gollora:ignored is not a directive",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := parseSuppressions(tt.line)
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("got  %+v\nwant %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestApplySuppressions(t *testing.T) {
+    files := []models.FileToAnalyze{
+        {Path: "main.go", Content: `package main
+
+func run() {
+    f.Close() // gollora:ignore errcheck -- closed by the caller
+    // gollora:ignore gosec:G104
+    w.Write(data)
+    msg := "// gollora:ignore -- not a comment"
+    w.Write(data)
+}
+`},
+        {Path: "gen.go", Content: "// gollora:ignore-file -- generated code\npackage main\n"},
+        {Path: "other.go", Content: "package main\n"},
+    }
+
+    tests := []struct {
+        name  string
+        issue models.CodeIssue
+        want  *models.Suppression
+    }{
+        {
+            name:  "same line",
+            issue: models.CodeIssue{File: "main.go", Line: 4, Tool: "golangci-lint", RuleID: "errcheck"},
+            want:  &models.Suppression{Scope: "line", Line: 4, Directive: "// gollora:ignore errcheck -- closed by the caller", Justification: "closed by the caller"},
+        },
+        {
+            name:  "other rule on the same line",
+            issue: models.CodeIssue{File: "main.go", Line: 4, Tool: "golangci-lint", RuleID: "unused"},
+        },
+        {
+            name:  "next line with tool and rule",
+            issue: models.CodeIssue{File: "main.go", Line: 6, Tool: "gosec", RuleID: "G104"},
+            want:  &models.Suppression{Scope: "line", Line: 5, Directive: "// gollora:ignore gosec:G104"},
+        },
+        {
+            name:  "rule from another tool",
+            issue: models.CodeIssue{File: "main.go", Line: 6, Tool: "golangci-lint", RuleID: "G104"},
+        },
+        {
+            name:  "two lines below",
+            issue: models.CodeIssue{File: "main.go", Line: 7, Tool: "gosec", RuleID: "G104"},
+        },
+        {
+            name:  "directive in a string literal",
+            issue: models.CodeIssue{File: "main.go", Line: 8, Tool: "gosec", RuleID: "G104"},
+        },
+        {
+            name:  "file scope",
+            issue: models.CodeIssue{File: "gen.go", Line: 40, Tool: "staticcheck", RuleID: "SA4006"},
+            want:  &models.Suppression{Scope: "file", Line: 1, Directive: "// gollora:ignore-file -- generated code", Justification: "generated code"},
+        },
+        {
+            name:  "file without directives",
+            issue: models.CodeIssue{File: "other.go", Line: 1, Tool: "gosec", RuleID: "G104"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            issues := []models.CodeIssue{tt.issue}
+            count := ApplySuppressions(issues, files)
+
+            wantCount := 0
+            if tt.want != nil {
+                wantCount = 1
+            }
+            if count != wantCount {
+                t.Errorf("suppressed %d issues, want %d", count, wantCount)
+            }
+            if !reflect.DeepEqual(issues[0].Suppression, tt.want) {
+                t.Errorf("suppression = %+v, want %+v", issues[0].Suppression, tt.want)
+            }
+            if tt.want != nil && issues[0].Status != models.StatusSuppressed {
+                t.Errorf("status = %q, want %q", issues[0].Status, models.StatusSuppressed)
+            }
+        })
+    }
+}
@@ -0,0 +1,144 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// WorkflowAnalyzer analyzes CI pipeline definitions (GitHub Actions
+// workflows) using actionlint.
+type WorkflowAnalyzer struct {
+    tools []models.Tool
+}
+
+// NewWorkflowAnalyzer creates a new WorkflowAnalyzer.
+func NewWorkflowAnalyzer(tools []models.Tool) *WorkflowAnalyzer {
+    return &WorkflowAnalyzer{
+        tools: tools,
+    }
+}
+
+// Analyze runs the configured tools over changed workflow files. Other YAML
+// files are ignored since actionlint only understands workflow syntax.
+func (a *WorkflowAnalyzer) Analyze(ctx context.Context, repoPath string, files []models.FileToAnalyze) ([]models.CodeIssue, error) {
+    var allIssues []models.CodeIssue
+
+    var workflows []string
+    for _, file := range files {
+        if isGitHubWorkflow(file.Path) {
+            workflows = append(workflows, file.Path)
+        }
+    }
+
+    if len(workflows) == 0 {
+        return nil, nil
+    }
+
+    utils.LogWithLocation(utils.Info, "Analyzing %d workflow files", len(workflows))
+
+    for _, tool := range a.tools {
+        if !tool.Enabled {
+            continue
+        }
+
+        utils.LogWithLocation(utils.Info, "Running tool: %s", tool.Name)
+
+        switch tool.Name {
+        case "actionlint":
+            issues, err := a.runActionlint(ctx, repoPath, workflows, tool)
+            if err != nil {
+                utils.LogWithLocation(utils.Error, "Error running actionlint: %v", err)
+                continue
+            }
+            utils.LogWithLocation(utils.Info, "Found %d issues from actionlint", len(issues))
+            allIssues = append(allIssues, issues...)
+        default:
+            utils.LogWithLocation(utils.Warn, "Unknown workflow tool: %s", tool.Name)
+        }
+    }
+
+    return allIssues, nil
+}
+
+func isGitHubWorkflow(path string) bool {
+    path = filepath.ToSlash(path)
+    ext := strings.ToLower(filepath.Ext(path))
+    return (ext == ".yml" || ext == ".yaml") && strings.Contains("/"+path, "/.github/workflows/")
+}
+
+func (a *WorkflowAnalyzer) runActionlint(ctx context.Context, repoPath string, files []string, tool models.Tool) ([]models.CodeIssue, error) {
+    args := append([]string{}, tool.Args...)
+    if !containsArg(args, "-format") {
+        args = append(args, "-format", "{{json .}}")
+    }
+    args = append(args, files...)
+
+    output, err := runToolCommand(ctx, repoPath, tool.Command, args)
+    if err != nil {
+        return nil, err
+    }
+    return parseActionlintJSON(output, repoPath)
+}
+
+// parseActionlintJSON converts actionlint's JSON template output into CodeIssues.
+func parseActionlintJSON(data []byte, repoPath string) ([]models.CodeIssue, error) {
+    trimmed := strings.TrimSpace(string(data))
+    if trimmed == "" || trimmed == "null" {
+        return nil, nil
+    }
+
+    var results []struct {
+        Message  string `json:"message"`
+        Filepath string `json:"filepath"`
+        Line     int    `json:"line"`
+        Column   int    `json:"column"`
+        Kind     string `json:"kind"`
+        Snippet  string `json:"snippet"`
+    }
+    if err := json.Unmarshal([]byte(trimmed), &results); err != nil {
+        return nil, fmt.Errorf("failed to parse actionlint output: %v", err)
+    }
+
+    var issues []models.CodeIssue
+    for _, r := range results {
+        severity, issueType := actionlintClassify(r.Kind)
+        issues = append(issues, models.CodeIssue{
+            Title:       fmt.Sprintf("%s: %s", r.Kind, shortenText(r.Message, 60)),
+            Description: r.Message,
+            File:        repoRelativePath(repoPath, r.Filepath),
+            Line:        r.Line,
+            Column:      r.Column,
+            Severity:    severity,
+            Type:        issueType,
+            Tool:        "actionlint",
+            RuleID:      r.Kind,
+            Code:        r.Snippet,
+            URL:         "https://github.com/rhysd/actionlint/blob/main/docs/checks.md",
+        })
+    }
+
+    return issues, nil
+}
+
+// actionlintClassify maps an actionlint check kind to a severity and type.
+// Syntax and expression errors break the pipeline outright.
+func actionlintClassify(kind string) (models.IssueSeverity, models.IssueType) {
+    switch kind {
+    case "credentials", "permissions":
+        return models.Error, models.Security
+    case "syntax-check", "expression", "job-needs", "matrix", "events", "action", "id":
+        return models.Error, models.Bug
+    case "shellcheck", "pyflakes":
+        return models.Warning, models.Bug
+    case "deprecated-commands":
+        return models.Warning, models.Maintainability
+    default:
+        return models.Warning, models.CodeStyle
+    }
+}
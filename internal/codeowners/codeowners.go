@@ -0,0 +1,207 @@
+// Package codeowners parses GitHub and GitLab CODEOWNERS files and resolves
+// the owners of repository paths.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/policy"
+)
+
+// Locations lists where GitHub and GitLab look for a CODEOWNERS file, in
+// the order they are tried.
+var Locations = []string{
+    ".github/CODEOWNERS",
+    ".gitlab/CODEOWNERS",
+    "CODEOWNERS",
+    "docs/CODEOWNERS",
+}
+
+// sectionHeader matches GitLab section headers such as "[Backend]",
+// "^[Docs]" or "[Database][2] @db-team". The brackets must be followed by
+// the end of the line or whitespace, so a GitHub pattern that starts with a
+// character class, such as "[Bb]uild/", isn't taken for a section.
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?(?:\s+(.*))?$`)
+
+// Rule is one pattern line of a CODEOWNERS file.
+type Rule struct {
+    Pattern string
+    Owners  []string
+    Line    int
+}
+
+// Section is a GitLab CODEOWNERS section. GitHub files have a single
+// unnamed section.
+type Section struct {
+    Name  string
+    Rules []Rule
+}
+
+// Ruleset is a parsed CODEOWNERS file.
+type Ruleset struct {
+    Path     string
+    Sections []Section
+}
+
+// Load finds and parses the CODEOWNERS file in repoPath. It returns nil
+// without error when the repository has none.
+func Load(repoPath string) (*Ruleset, error) {
+    for _, location := range Locations {
+        path := filepath.Join(repoPath, location)
+        f, err := os.Open(path)
+        if os.IsNotExist(err) {
+            continue
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to open %s: %v", location, err)
+        }
+        defer f.Close()
+
+        rs, err := Parse(bufio.NewScanner(f))
+        if err != nil {
+            return nil, fmt.Errorf("failed to parse %s: %v", location, err)
+        }
+        rs.Path = location
+        return rs, nil
+    }
+    return nil, nil
+}
+
+// Parse reads CODEOWNERS rules. Entries in a GitLab section without owners
+// inherit the section's default owners.
+func Parse(scanner *bufio.Scanner) (*Ruleset, error) {
+    rs := &Ruleset{Sections: []Section{{}}}
+    var defaults []string
+
+    lineNum := 0
+    for scanner.Scan() {
+        lineNum++
+        line := strings.TrimSpace(stripComment(scanner.Text()))
+        if line == "" {
+            continue
+        }
+
+        if m := sectionHeader.FindStringSubmatch(line); m != nil && allOwners(strings.Fields(m[2])) {
+            rs.Sections = append(rs.Sections, Section{Name: strings.TrimSpace(m[1])})
+            defaults = strings.Fields(m[2])
+            continue
+        }
+
+        fields := splitFields(line)
+        rule := Rule{Pattern: fields[0], Owners: fields[1:], Line: lineNum}
+        if len(rule.Owners) == 0 {
+            rule.Owners = defaults
+        }
+        current := &rs.Sections[len(rs.Sections)-1]
+        current.Rules = append(current.Rules, rule)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return rs, nil
+}
+
+// Owners returns the owners of a repo-relative path. Within a section the
+// last matching rule wins; owners from every section are combined, in the
+// order the sections appear.
+func (rs *Ruleset) Owners(path string) []string {
+    if rs == nil {
+        return nil
+    }
+    path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+
+    var owners []string
+    seen := make(map[string]bool)
+    for _, section := range rs.Sections {
+        for i := len(section.Rules) - 1; i >= 0; i-- {
+            rule := section.Rules[i]
+            if !Match(rule.Pattern, path) {
+                continue
+            }
+            for _, owner := range rule.Owners {
+                if !seen[owner] {
+                    seen[owner] = true
+                    owners = append(owners, owner)
+                }
+            }
+            break
+        }
+    }
+    return owners
+}
+
+// Match reports whether a CODEOWNERS pattern matches path, following the
+// gitignore rules both GitHub and GitLab use: a pattern with a leading or
+// inner slash is anchored to the repository root, other patterns match at
+// any depth, and a pattern naming a directory covers everything below it.
+// Wildcards don't descend: "docs/*" owns docs/a.md but not docs/api/a.md.
+func Match(pattern, path string) bool {
+    anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+    directory := strings.HasSuffix(pattern, "/")
+    pattern = strings.Trim(pattern, "/")
+    if pattern == "" {
+        return true
+    }
+    if !directory {
+        last := pattern[strings.LastIndex(pattern, "/")+1:]
+        directory = !strings.ContainsAny(last, "*?[")
+    }
+    if !anchored {
+        pattern = "**/" + pattern
+    }
+    if policy.MatchGlob(pattern, path) {
+        return true
+    }
+    return directory && policy.MatchGlob(pattern+"/**", path)
+}
+
+// allOwners reports whether every field names a user, group or email, as
+// the default owners after a section header must.
+func allOwners(fields []string) bool {
+    for _, field := range fields {
+        if !strings.Contains(field, "@") {
+            return false
+        }
+    }
+    return true
+}
+
+// stripComment removes a trailing comment, keeping escaped "\#".
+func stripComment(line string) string {
+    for i := 0; i < len(line); i++ {
+        if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+            return line[:i]
+        }
+    }
+    return line
+}
+
+// splitFields splits on whitespace, keeping "\ " escaped spaces in patterns.
+func splitFields(line string) []string {
+    var fields []string
+    var current strings.Builder
+    for i := 0; i < len(line); i++ {
+        c := line[i]
+        switch {
+        case c == '\\' && i+1 < len(line) && (line[i+1] == ' ' || line[i+1] == '#'):
+            current.WriteByte(line[i+1])
+            i++
+        case c == ' ' || c == '\t':
+            if current.Len() > 0 {
+                fields = append(fields, current.String())
+                current.Reset()
+            }
+        default:
+            current.WriteByte(c)
+        }
+    }
+    if current.Len() > 0 {
+        fields = append(fields, current.String())
+    }
+    return fields
+}
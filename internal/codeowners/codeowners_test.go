@@ -0,0 +1,163 @@
+package codeowners
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseString(t *testing.T, content string) *Ruleset {
+    t.Helper()
+    rs, err := Parse(bufio.NewScanner(strings.NewReader(content)))
+    if err != nil {
+        t.Fatalf("Parse failed: %v", err)
+    }
+    return rs
+}
+
+func TestParse(t *testing.T) {
+    tests := []struct {
+        name    string
+        content string
+        want    []Section
+    }{
+        {
+            name: "github file",
+            content: `# Default owners
+* @org/everyone
+*.go @gophers @lead # Go code
+/docs/ docs@example.com
+`,
+            want: []Section{{Rules: []Rule{
+                {Pattern: "*", Owners: []string{"@org/everyone"}, Line: 2},
+                {Pattern: "*.go", Owners: []string{"@gophers", "@lead"}, Line: 3},
+                {Pattern: "/docs/", Owners: []string{"docs@example.com"}, Line: 4},
+            }}},
+        },
+        {
+            name: "github patterns starting with a character class",
+            content: `[Bb]uild/ @build-team
+[Dd]ocs/*.md @writers
+[Ll]ib @lib-team
+`,
+            want: []Section{{Rules: []Rule{
+                {Pattern: "[Bb]uild/", Owners: []string{"@build-team"}, Line: 1},
+                {Pattern: "[Dd]ocs/*.md", Owners: []string{"@writers"}, Line: 2},
+                {Pattern: "[Ll]ib", Owners: []string{"@lib-team"}, Line: 3},
+            }}},
+        },
+        {
+            name: "gitlab sections",
+            content: `*.md @writers
+
+[Backend] @backend-team
+/app/
+/lib/ @lib-owner
+
+^[Database][2] @db-team @dba
+/db/
+
+[Docs]
+/docs/
+`,
+            want: []Section{
+                {Rules: []Rule{
+                    {Pattern: "*.md", Owners: []string{"@writers"}, Line: 1},
+                }},
+                {Name: "Backend", Rules: []Rule{
+                    {Pattern: "/app/", Owners: []string{"@backend-team"}, Line: 4},
+                    {Pattern: "/lib/", Owners: []string{"@lib-owner"}, Line: 5},
+                }},
+                {Name: "Database", Rules: []Rule{
+                    {Pattern: "/db/", Owners: []string{"@db-team", "@dba"}, Line: 8},
+                }},
+                {Name: "Docs", Rules: []Rule{
+                    {Pattern: "/docs/", Owners: []string{}, Line: 11},
+                }},
+            },
+        },
+        {
+            name: "escaped spaces and hashes",
+            content: `/my\ docs/ @writers
+/issue\#1.md @triage
+`,
+            want: []Section{{Rules: []Rule{
+                {Pattern: "/my docs/", Owners: []string{"@writers"}, Line: 1},
+                {Pattern: "/issue#1.md", Owners: []string{"@triage"}, Line: 2},
+            }}},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rs := parseString(t, tt.content)
+            if !reflect.DeepEqual(rs.Sections, tt.want) {
+                t.Errorf("sections:\ngot  %+v\nwant %+v", rs.Sections, tt.want)
+            }
+        })
+    }
+}
+
+func TestMatch(t *testing.T) {
+    tests := []struct {
+        pattern string
+        path    string
+        want    bool
+    }{
+        {"*", "main.go", true},
+        {"*", "cmd/main.go", true},
+        {"*.go", "cmd/main.go", true},
+        {"*.go", "main.py", false},
+        {"/docs/", "docs/a.md", true},
+        {"/docs/", "docs/api/a.md", true},
+        {"/docs/", "src/docs/a.md", false},
+        {"docs/", "src/docs/a.md", true},
+        {"docs/*", "docs/a.md", true},
+        {"docs/*", "docs/api/a.md", false},
+        {"/docs/**", "docs/api/a.md", true},
+        {"**/logs", "a/b/logs/x.log", true},
+        {"apps/web", "apps/web/index.js", true},
+        {"apps/web", "src/apps/web/index.js", false},
+        {"/build/logs/", "build/logs/today.log", true},
+        {"[Bb]uild/", "Build/out.o", true},
+        {"[Bb]uild/", "build/out.o", true},
+        {"[Bb]uild/", "rebuild/out.o", false},
+        {"/", "anything.go", true},
+    }
+    for _, tt := range tests {
+        if got := Match(tt.pattern, tt.path); got != tt.want {
+            t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+        }
+    }
+}
+
+func TestOwners(t *testing.T) {
+    rs := parseString(t, `* @org/everyone
+*.go @gophers
+/cmd/ @cli-team
+
+[Security] @security
+/internal/auth/
+`)
+
+    tests := []struct {
+        path string
+        want []string
+    }{
+        {"README.md", []string{"@org/everyone"}},
+        {"internal/models/result.go", []string{"@gophers"}},
+        {"./cmd/main.go", []string{"@cli-team"}},
+        {"internal/auth/token.go", []string{"@gophers", "@security"}},
+    }
+    for _, tt := range tests {
+        if got := rs.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+        }
+    }
+
+    var none *Ruleset
+    if got := none.Owners("main.go"); got != nil {
+        t.Errorf("nil ruleset Owners = %v, want nil", got)
+    }
+}
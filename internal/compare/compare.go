@@ -0,0 +1,226 @@
+// Package compare diffs two analysis results by issue fingerprint, e.g. the
+// JSON reports of two releases, to show how code quality changed.
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// Side describes one of the compared analyses.
+type Side struct {
+    Path       string    `json:"path,omitempty"`
+    ID         string    `json:"id"`
+    Repo       string    `json:"repo,omitempty"`
+    Branch     string    `json:"branch,omitempty"`
+    Commit     string    `json:"commit,omitempty"`
+    AnalyzedAt time.Time `json:"analyzed_at"`
+    Issues     int       `json:"issues"`
+    Suppressed int       `json:"suppressed"`
+}
+
+// ShortCommit returns the first 12 characters of the commit SHA.
+func (s Side) ShortCommit() string {
+    if len(s.Commit) > 12 {
+        return s.Commit[:12]
+    }
+    return s.Commit
+}
+
+// Delta is the issue count for one key (a severity, type or file) in the
+// base and head analyses.
+type Delta struct {
+    Key    string `json:"key"`
+    Base   int    `json:"base"`
+    Head   int    `json:"head"`
+    Change int    `json:"change"`
+}
+
+// Comparison lists the issues introduced, fixed and kept between a base and
+// a head analysis. Suppressed issues are left out of the diff.
+type Comparison struct {
+    Base      Side               `json:"base"`
+    Head      Side               `json:"head"`
+    New       []models.CodeIssue `json:"new"`
+    Fixed     []models.CodeIssue `json:"fixed"`
+    Unchanged []models.CodeIssue `json:"unchanged"`
+
+    BySeverity []Delta `json:"by_severity"`
+    ByType     []Delta `json:"by_type"`
+    ByFile     []Delta `json:"by_file"`
+}
+
+// Load reads an AnalysisResult written by the JSON exporter.
+func Load(path string) (*models.AnalysisResult, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read analysis result: %v", err)
+    }
+
+    var result models.AnalysisResult
+    if err := json.Unmarshal(data, &result); err != nil {
+        return nil, fmt.Errorf("failed to parse analysis result %s: %v", path, err)
+    }
+    return &result, nil
+}
+
+// Files loads two exported results and compares them.
+func Files(basePath, headPath string) (*Comparison, error) {
+    base, err := Load(basePath)
+    if err != nil {
+        return nil, err
+    }
+    head, err := Load(headPath)
+    if err != nil {
+        return nil, err
+    }
+
+    c := Compare(base, head)
+    c.Base.Path = basePath
+    c.Head.Path = headPath
+    return c, nil
+}
+
+// Compare matches the active issues of base and head by fingerprint. When
+// several issues share a fingerprint, the extra ones in head are new and the
+// extra ones in base fixed. Unchanged issues are reported as they appear in
+// head, so their lines are current.
+func Compare(base, head *models.AnalysisResult) *Comparison {
+    baseIssues := activeIssues(base)
+    headIssues := activeIssues(head)
+
+    remaining := make(map[string][]models.CodeIssue)
+    for _, issue := range baseIssues {
+        remaining[issue.Fingerprint] = append(remaining[issue.Fingerprint], issue)
+    }
+
+    c := &Comparison{
+        Base: side(base, len(baseIssues)),
+        Head: side(head, len(headIssues)),
+    }
+    for _, issue := range headIssues {
+        if matches := remaining[issue.Fingerprint]; len(matches) > 0 {
+            remaining[issue.Fingerprint] = matches[1:]
+            c.Unchanged = append(c.Unchanged, issue)
+        } else {
+            c.New = append(c.New, issue)
+        }
+    }
+    for _, issue := range baseIssues {
+        if matches := remaining[issue.Fingerprint]; len(matches) > 0 {
+            remaining[issue.Fingerprint] = matches[1:]
+            c.Fixed = append(c.Fixed, issue)
+        }
+    }
+
+    sortIssues(c.New)
+    sortIssues(c.Fixed)
+    sortIssues(c.Unchanged)
+
+    c.BySeverity = deltas(baseIssues, headIssues, func(issue models.CodeIssue) string { return string(issue.Severity) })
+    sort.SliceStable(c.BySeverity, func(i, j int) bool {
+        return models.IssueSeverity(c.BySeverity[i].Key).Rank() > models.IssueSeverity(c.BySeverity[j].Key).Rank()
+    })
+    c.ByType = deltas(baseIssues, headIssues, func(issue models.CodeIssue) string { return string(issue.Type) })
+    c.ByFile = deltas(baseIssues, headIssues, func(issue models.CodeIssue) string { return issue.File })
+    return c
+}
+
+// ChangedFiles returns the file deltas whose issue count changed.
+func (c *Comparison) ChangedFiles() []Delta {
+    var files []Delta
+    for _, d := range c.ByFile {
+        if d.Change != 0 {
+            files = append(files, d)
+        }
+    }
+    return files
+}
+
+// activeIssues returns the unsuppressed issues, filling in fingerprints that
+// older exports may lack.
+func activeIssues(result *models.AnalysisResult) []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, issue := range result.Issues {
+        if issue.Status == models.StatusSuppressed {
+            continue
+        }
+        if issue.Fingerprint == "" {
+            issue.Fingerprint = models.ComputeFingerprint(issue)
+        }
+        issues = append(issues, issue)
+    }
+    return issues
+}
+
+func side(result *models.AnalysisResult, active int) Side {
+    return Side{
+        ID:         result.ID,
+        Repo:       result.Event.RepoFullName,
+        Branch:     result.Event.Branch,
+        Commit:     result.Event.HeadCommit,
+        AnalyzedAt: result.AnalyzedAt,
+        Issues:     active,
+        Suppressed: len(result.Issues) - active,
+    }
+}
+
+func sortIssues(issues []models.CodeIssue) {
+    sort.SliceStable(issues, func(i, j int) bool {
+        if issues[i].Severity.Rank() != issues[j].Severity.Rank() {
+            return issues[i].Severity.Rank() > issues[j].Severity.Rank()
+        }
+        if issues[i].File != issues[j].File {
+            return issues[i].File < issues[j].File
+        }
+        return issues[i].Line < issues[j].Line
+    })
+}
+
+// deltas counts issues per key on both sides, largest change first. Keys
+// with no change are kept so the table shows every type or file involved.
+func deltas(base, head []models.CodeIssue, key func(models.CodeIssue) string) []Delta {
+    index := make(map[string]*Delta)
+    var out []*Delta
+    get := func(k string) *Delta {
+        d, ok := index[k]
+        if !ok {
+            d = &Delta{Key: k}
+            index[k] = d
+            out = append(out, d)
+        }
+        return d
+    }
+    for _, issue := range base {
+        get(key(issue)).Base++
+    }
+    for _, issue := range head {
+        get(key(issue)).Head++
+    }
+
+    result := make([]Delta, 0, len(out))
+    for _, d := range out {
+        d.Change = d.Head - d.Base
+        result = append(result, *d)
+    }
+    sort.SliceStable(result, func(i, j int) bool {
+        ai, aj := abs(result[i].Change), abs(result[j].Change)
+        if ai != aj {
+            return ai > aj
+        }
+        return result[i].Key < result[j].Key
+    })
+    return result
+}
+
+func abs(n int) int {
+    if n < 0 {
+        return -n
+    }
+    return n
+}
@@ -0,0 +1,549 @@
+package depgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Sources of the transitive Go module graph.
+const (
+    SourceGoModGraph  = "go mod graph"
+    SourceModuleCache = "module cache"
+)
+
+// GoModules is a main module's resolved module graph, kept alongside the
+// nodes and edges it added to a DependencyGraph.
+type GoModules struct {
+    Root string        // the main module's node ID
+    File *modfile.File // the main module's go.mod
+    // Source is where the transitive edges came from, and GraphError why
+    // "go mod graph" couldn't be used when the module cache was walked
+    // instead.
+    Source     string
+    GraphError error
+    // BuildList maps each module path to its selected version, the highest
+    // version in the graph that isn't excluded.
+    BuildList map[string]string
+    CacheDir  string
+}
+
+// ModuleCacheDir returns the Go module cache, GOMODCACHE or GOPATH/pkg/mod.
+func ModuleCacheDir() string {
+    if dir := os.Getenv("GOMODCACHE"); dir != "" {
+        return dir
+    }
+    gopath := os.Getenv("GOPATH")
+    if gopath == "" {
+        home, err := os.UserHomeDir()
+        if err != nil {
+            return ""
+        }
+        gopath = filepath.Join(home, "go")
+    }
+    return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// AddGoModules adds the module graph of the main module in dir. The main
+// module's own requirements come from its go.mod; the transitive edges from
+// "go mod graph", run offline, or failing that from the go.mod files in the
+// module cache, following only the modules listed in go.sum as the go
+// command does.
+func AddGoModules(ctx context.Context, g *models.DependencyGraph, dir string) (*GoModules, error) {
+    path := filepath.Join(dir, "go.mod")
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    file, err := modfile.Parse(path, content, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+    }
+    if file.Module == nil {
+        return nil, fmt.Errorf("%s has no module directive", path)
+    }
+
+    mods := &GoModules{File: file, CacheDir: ModuleCacheDir()}
+    mods.Root = g.AddNode(models.DependencyNode{
+        Name:      file.Module.Mod.Path,
+        Ecosystem: EcosystemGo,
+        Root:      true,
+    })
+    for _, require := range file.Require {
+        g.AddEdge(models.DependencyEdge{
+            From:   mods.Root,
+            To:     mods.addNode(g, require.Mod),
+            Direct: !require.Indirect,
+            Scope:  models.ScopeRuntime,
+        })
+    }
+
+    // The go command follows local replacements wherever they point, so
+    // the graph comes from the cache when one leaves the repository.
+    var pairs [][2]module.Version
+    err = mods.checkLocalReplacements(dir)
+    if err == nil {
+        pairs, err = goModGraph(ctx, dir)
+    }
+    mods.Source = SourceGoModGraph
+    if err != nil {
+        mods.GraphError = err
+        mods.Source = SourceModuleCache
+        pairs = mods.cacheGraph(dir)
+    }
+    for _, pair := range pairs {
+        from := mods.Root
+        if pair[0].Version != "" {
+            from = mods.addNode(g, pair[0])
+        }
+        g.AddEdge(models.DependencyEdge{
+            From:   from,
+            To:     mods.addNode(g, pair[1]),
+            Direct: from == mods.Root && mods.requiredDirectly(pair[1].Path),
+            Scope:  models.ScopeRuntime,
+        })
+    }
+
+    mods.BuildList = buildList(g, file)
+    return mods, nil
+}
+
+func (m *GoModules) addNode(g *models.DependencyGraph, mod module.Version) string {
+    node := models.DependencyNode{
+        Name:      mod.Path,
+        Version:   mod.Version,
+        Ecosystem: EcosystemGo,
+    }
+    if r := m.replacement(mod); r != nil {
+        node.Replacement = formatModule(r.New)
+    }
+    return g.AddNode(node)
+}
+
+func (m *GoModules) requiredDirectly(path string) bool {
+    for _, require := range m.File.Require {
+        if require.Mod.Path == path {
+            return !require.Indirect
+        }
+    }
+    return false
+}
+
+// replacement returns the replace directive that applies to mod, preferring
+// one for its exact version over one for all versions.
+func (m *GoModules) replacement(mod module.Version) *modfile.Replace {
+    var wildcard *modfile.Replace
+    for _, r := range m.File.Replace {
+        if r.Old.Path != mod.Path {
+            continue
+        }
+        if r.Old.Version == mod.Version {
+            return r
+        }
+        if r.Old.Version == "" {
+            wildcard = r
+        }
+    }
+    return wildcard
+}
+
+func formatModule(mod module.Version) string {
+    if mod.Version == "" {
+        return mod.Path
+    }
+    return mod.Path + "@" + mod.Version
+}
+
+// goModGraph runs "go mod graph" without network access, so it only
+// succeeds when every module it needs is already in the module cache.
+func goModGraph(ctx context.Context, dir string) ([][2]module.Version, error) {
+    cmd := exec.CommandContext(ctx, "go", "mod", "graph")
+    cmd.Dir = dir
+    cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off", "GOWORK=off", "GOTOOLCHAIN=local")
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    out, err := cmd.Output()
+    if err != nil {
+        if msg := strings.TrimSpace(stderr.String()); msg != "" {
+            return nil, fmt.Errorf("go mod graph failed: %v: %s", err, msg)
+        }
+        return nil, fmt.Errorf("go mod graph failed: %v", err)
+    }
+
+    var pairs [][2]module.Version
+    for _, line := range strings.Split(string(out), "\n") {
+        fields := strings.Fields(line)
+        if len(fields) != 2 {
+            continue
+        }
+        from, to := parseModule(fields[0]), parseModule(fields[1])
+        // The go and toolchain lines are Go version requirements, not modules.
+        if to.Path == "go" || to.Path == "toolchain" {
+            continue
+        }
+        pairs = append(pairs, [2]module.Version{from, to})
+    }
+    return pairs, nil
+}
+
+func parseModule(s string) module.Version {
+    path, version, _ := strings.Cut(s, "@")
+    return module.Version{Path: path, Version: version}
+}
+
+// cacheGraph rebuilds the module graph from the go.mod files in the module
+// cache. Modules whose go.mod isn't cached are kept as leaves.
+func (m *GoModules) cacheGraph(dir string) [][2]module.Version {
+    listed := goSumModules(filepath.Join(dir, "go.sum"))
+
+    var pairs [][2]module.Version
+    visited := make(map[module.Version]bool)
+    var queue []module.Version
+    for _, require := range m.File.Require {
+        queue = append(queue, require.Mod)
+    }
+    for len(queue) > 0 {
+        mod := queue[0]
+        queue = queue[1:]
+        if visited[mod] {
+            continue
+        }
+        visited[mod] = true
+
+        file := m.readModFile(dir, mod)
+        if file == nil {
+            continue
+        }
+        for _, require := range file.Require {
+            if listed != nil && !listed[require.Mod] {
+                continue // pruned: the go command never loaded it
+            }
+            pairs = append(pairs, [2]module.Version{mod, require.Mod})
+            queue = append(queue, require.Mod)
+        }
+    }
+    return pairs
+}
+
+// readModFile returns the go.mod of mod, or of its replacement, or nil when
+// it isn't available offline.
+func (m *GoModules) readModFile(dir string, mod module.Version) *modfile.File {
+    path := m.cachePath(mod, ".mod")
+    if r := m.replacement(mod); r != nil {
+        if r.New.Version == "" {
+            path = resolveInRepo(dir, filepath.Join(filepath.FromSlash(r.New.Path), "go.mod"))
+        } else {
+            path = m.cachePath(r.New, ".mod")
+        }
+    }
+    if path == "" {
+        return nil
+    }
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil
+    }
+    file, err := modfile.ParseLax(path, content, nil)
+    if err != nil {
+        return nil
+    }
+    return file
+}
+
+// checkLocalReplacements returns an error for the first local replacement
+// that isn't a directory inside the repository at dir.
+func (m *GoModules) checkLocalReplacements(dir string) error {
+    for _, r := range m.File.Replace {
+        if r.New.Version == "" && resolveInRepo(dir, filepath.FromSlash(r.New.Path)) == "" {
+            return fmt.Errorf("replace %s => %s doesn't point inside the repository", formatModule(r.Old), r.New.Path)
+        }
+    }
+    return nil
+}
+
+// resolveInRepo resolves rel against the repository at dir, following
+// symlinks. It returns "" for absolute paths, for paths that don't exist and
+// for paths that leave the repository, so a go.mod can't make the analysis
+// read files elsewhere on the host.
+func resolveInRepo(dir, rel string) string {
+    if dir == "" || filepath.IsAbs(rel) {
+        return ""
+    }
+    root, err := filepath.EvalSymlinks(dir)
+    if err != nil {
+        return ""
+    }
+    resolved, err := filepath.EvalSymlinks(filepath.Join(root, rel))
+    if err != nil {
+        return ""
+    }
+    if inside, err := filepath.Rel(root, resolved); err != nil || (inside != "." && !filepath.IsLocal(inside)) {
+        return ""
+    }
+    return resolved
+}
+
+// cachePath returns where the module cache keeps a download file of mod,
+// e.g. its ".mod" or ".info".
+func (m *GoModules) cachePath(mod module.Version, ext string) string {
+    dir := m.cacheVersionsDir(mod.Path)
+    version, err := module.EscapeVersion(mod.Version)
+    if dir == "" || err != nil {
+        return ""
+    }
+    return filepath.Join(dir, version+ext)
+}
+
+func (m *GoModules) cacheVersionsDir(path string) string {
+    escaped, err := module.EscapePath(path)
+    if m.CacheDir == "" || err != nil {
+        return ""
+    }
+    return filepath.Join(m.CacheDir, "cache", "download", filepath.FromSlash(escaped), "@v")
+}
+
+// goSumModules returns the modules whose go.mod is checksummed in go.sum,
+// or nil when there is no go.sum.
+func goSumModules(path string) map[module.Version]bool {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil
+    }
+    defer file.Close()
+
+    listed := make(map[module.Version]bool)
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        fields := strings.Fields(scanner.Text())
+        if len(fields) != 3 {
+            continue
+        }
+        version := strings.TrimSuffix(fields[1], "/go.mod")
+        listed[module.Version{Path: fields[0], Version: version}] = true
+    }
+    return listed
+}
+
+// buildList selects the highest non-excluded version of each module in the
+// graph, as minimal version selection does.
+func buildList(g *models.DependencyGraph, file *modfile.File) map[string]string {
+    excluded := make(map[module.Version]bool)
+    for _, e := range file.Exclude {
+        excluded[e.Mod] = true
+    }
+
+    selected := make(map[string]string)
+    for _, n := range g.Nodes {
+        if n.Ecosystem != EcosystemGo || n.Root || n.Version == "" {
+            continue
+        }
+        if excluded[module.Version{Path: n.Name, Version: n.Version}] {
+            continue
+        }
+        if current, ok := selected[n.Name]; !ok || semver.Compare(n.Version, current) > 0 {
+            selected[n.Name] = n.Version
+        }
+    }
+    return selected
+}
+
+// Check reports replaced modules, selected versions that their authors
+// retracted, modules selected at more than one major version and
+// requirements pinned to pseudo-versions, as Dependency issues on go.mod.
+func (m *GoModules) Check() []models.CodeIssue {
+    var issues []models.CodeIssue
+    issues = append(issues, m.checkReplacements()...)
+    issues = append(issues, m.checkRetractions()...)
+    issues = append(issues, m.checkMajorVersions()...)
+    issues = append(issues, m.checkPseudoVersions()...)
+    return issues
+}
+
+func (m *GoModules) issue(rule string, severity models.IssueSeverity, line int, title, description, code string) models.CodeIssue {
+    return models.CodeIssue{
+        Title:       title,
+        Description: description,
+        File:        "go.mod",
+        Line:        line,
+        Severity:    severity,
+        Type:        models.Dependency,
+        Tool:        "go-mod",
+        RuleID:      rule,
+        Code:        code,
+    }
+}
+
+// selectedPaths returns the module paths in the build list, sorted.
+func (m *GoModules) selectedPaths() []string {
+    paths := make([]string, 0, len(m.BuildList))
+    for path := range m.BuildList {
+        paths = append(paths, path)
+    }
+    sort.Strings(paths)
+    return paths
+}
+
+// requireLine returns the go.mod line requiring path, or 0 when the main
+// module only depends on it transitively.
+func (m *GoModules) requireLine(path string) int {
+    for _, require := range m.File.Require {
+        if require.Mod.Path == path && require.Syntax != nil {
+            return require.Syntax.Start.Line
+        }
+    }
+    return 0
+}
+
+func (m *GoModules) checkReplacements() []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, r := range m.File.Replace {
+        line := 0
+        if r.Syntax != nil {
+            line = r.Syntax.Start.Line
+        }
+        code := fmt.Sprintf("replace %s => %s", formatModule(r.Old), formatModule(r.New))
+        if r.New.Version == "" {
+            issues = append(issues, m.issue("gomod-replace", models.Warning, line,
+                fmt.Sprintf("%s is replaced by a local directory", r.Old.Path),
+                fmt.Sprintf("%s is replaced by %s. Builds depend on that directory being present and can't be reproduced from the module proxy.", formatModule(r.Old), r.New.Path),
+                code))
+            continue
+        }
+        issues = append(issues, m.issue("gomod-replace", models.Info, line,
+            fmt.Sprintf("%s is replaced by %s", r.Old.Path, formatModule(r.New)),
+            fmt.Sprintf("%s is replaced by %s, so the build doesn't use the version other modules require.", formatModule(r.Old), formatModule(r.New)),
+            code))
+    }
+    return issues
+}
+
+// checkRetractions reads each selected module's retractions from the
+// highest version of its go.mod in the module cache. Modules without cached
+// go.mod files, and replaced modules, are skipped.
+func (m *GoModules) checkRetractions() []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, path := range m.selectedPaths() {
+        version := m.BuildList[path]
+        if m.replacement(module.Version{Path: path, Version: version}) != nil {
+            continue
+        }
+        for _, r := range m.retractions(path) {
+            if semver.Compare(version, r.Low) < 0 || semver.Compare(version, r.High) > 0 {
+                continue
+            }
+            description := fmt.Sprintf("The authors of %s retracted %s.", path, version)
+            if r.Rationale != "" {
+                description += " Rationale: " + strings.TrimSuffix(r.Rationale, ".") + "."
+            }
+            issues = append(issues, m.issue("gomod-retracted", models.Error, m.requireLine(path),
+                fmt.Sprintf("%s@%s is retracted", path, version),
+                description+" Upgrade to a version that isn't retracted.",
+                fmt.Sprintf("%s %s", path, version)))
+            break
+        }
+    }
+    return issues
+}
+
+func (m *GoModules) retractions(path string) []*modfile.Retract {
+    dir := m.cacheVersionsDir(path)
+    if dir == "" {
+        return nil
+    }
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        return nil
+    }
+
+    latest := ""
+    for _, entry := range entries {
+        name := entry.Name()
+        if !strings.HasSuffix(name, ".mod") {
+            continue
+        }
+        version, err := module.UnescapeVersion(strings.TrimSuffix(name, ".mod"))
+        if err != nil || !semver.IsValid(version) {
+            continue
+        }
+        if latest == "" || semver.Compare(version, latest) > 0 {
+            latest = version
+        }
+    }
+    if latest == "" {
+        return nil
+    }
+
+    file := m.readModFile("", module.Version{Path: path, Version: latest})
+    if file == nil {
+        return nil
+    }
+    return file.Retract
+}
+
+func (m *GoModules) checkMajorVersions() []models.CodeIssue {
+    byPrefix := make(map[string][]string)
+    var prefixes []string
+    for _, path := range m.selectedPaths() {
+        prefix, _, ok := module.SplitPathVersion(path)
+        if !ok {
+            continue
+        }
+        if _, seen := byPrefix[prefix]; !seen {
+            prefixes = append(prefixes, prefix)
+        }
+        byPrefix[prefix] = append(byPrefix[prefix], path)
+    }
+    sort.Strings(prefixes)
+
+    var issues []models.CodeIssue
+    for _, prefix := range prefixes {
+        paths := byPrefix[prefix]
+        if len(paths) < 2 {
+            continue
+        }
+
+        selected := make([]string, 0, len(paths))
+        line := 0
+        for _, path := range paths {
+            selected = append(selected, path+"@"+m.BuildList[path])
+            if line == 0 {
+                line = m.requireLine(path)
+            }
+        }
+        issues = append(issues, m.issue("gomod-multiple-majors", models.Warning, line,
+            fmt.Sprintf("%d major versions of %s are in the build", len(paths), prefix),
+            fmt.Sprintf("The build includes %s. Each major version is a separate module, so types and global state aren't shared between them.", strings.Join(selected, ", ")),
+            strings.Join(selected, " ")))
+    }
+    return issues
+}
+
+func (m *GoModules) checkPseudoVersions() []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, require := range m.File.Require {
+        if !module.IsPseudoVersion(require.Mod.Version) {
+            continue
+        }
+        rev, _ := module.PseudoVersionRev(require.Mod.Version)
+        line := 0
+        if require.Syntax != nil {
+            line = require.Syntax.Start.Line
+        }
+        issues = append(issues, m.issue("gomod-pseudo-version", models.Info, line,
+            fmt.Sprintf("%s is pinned to a pseudo-version", require.Mod.Path),
+            fmt.Sprintf("%s is required at %s, an untagged commit (%s). Prefer a tagged release once one includes the change you need.", require.Mod.Path, require.Mod.Version, rev),
+            fmt.Sprintf("%s %s", require.Mod.Path, require.Mod.Version)))
+    }
+    return issues
+}
@@ -0,0 +1,88 @@
+// Package depgraph builds dependency graphs from package manifests and
+// renders them as Mermaid, Graphviz DOT, GraphML or JSON.
+package depgraph
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// Ecosystems as recorded on graph nodes.
+const (
+    EcosystemGo   = "go"
+    EcosystemPyPI = "pypi"
+)
+
+// RequirementFiles maps the Python requirement files that are read to the
+// scope of their requirements.
+var RequirementFiles = []struct {
+    Name  string
+    Scope string
+}{
+    {"requirements.txt", models.ScopeRuntime},
+    {"requirements-dev.txt", models.ScopeDev},
+    {"requirements-test.txt", models.ScopeTest},
+}
+
+// requirementLine matches a requirement's name, optional extras and version
+// specifier, e.g. "requests[socks]>=2.0,<3".
+var requirementLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+
+// AddRequirements adds the requirements in the pip requirements file at
+// path as direct dependencies of the root project. Pinned requirements
+// ("==") get their version; others keep their specifier as the version.
+// Options such as "-r" and "-e" are skipped.
+func AddRequirements(g *models.DependencyGraph, path, project, scope string) error {
+    file, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    root := g.AddNode(models.DependencyNode{
+        Name:      project,
+        Ecosystem: EcosystemPyPI,
+        Root:      true,
+    })
+
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        line := scanner.Text()
+        if i := strings.Index(line, "#"); i >= 0 {
+            line = line[:i]
+        }
+        if i := strings.Index(line, ";"); i >= 0 {
+            line = line[:i] // environment marker
+        }
+        line = strings.TrimSpace(line)
+        if line == "" || strings.HasPrefix(line, "-") {
+            continue
+        }
+
+        m := requirementLine.FindStringSubmatch(line)
+        if m == nil {
+            continue
+        }
+        version := strings.ReplaceAll(m[2], " ", "")
+        if strings.HasPrefix(version, "==") && !strings.Contains(version, ",") {
+            version = strings.TrimPrefix(version, "==")
+        }
+
+        dep := g.AddNode(models.DependencyNode{
+            Name:      m[1],
+            Version:   version,
+            Ecosystem: EcosystemPyPI,
+        })
+        g.AddEdge(models.DependencyEdge{
+            From:   root,
+            To:     dep,
+            Direct: true,
+            Scope:  scope,
+        })
+    }
+    return scanner.Err()
+}
@@ -0,0 +1,250 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// EcosystemTitle returns the display name of an ecosystem.
+func EcosystemTitle(ecosystem string) string {
+    switch ecosystem {
+    case EcosystemGo:
+        return "Go"
+    case EcosystemPyPI:
+        return "Python"
+    }
+    return ecosystem
+}
+
+// Label returns how a node is shown: its name and version.
+func Label(node models.DependencyNode) string {
+    if node.Version == "" {
+        return node.Name
+    }
+    return node.Name + " " + node.Version
+}
+
+// edgeLabel names an edge's scope when it isn't the default.
+func edgeLabel(edge models.DependencyEdge) string {
+    if edge.Scope == models.ScopeRuntime {
+        return ""
+    }
+    return edge.Scope
+}
+
+// mermaidMaxEdges caps the edges drawn per ecosystem, so the summary graph
+// stays readable for large projects.
+const mermaidMaxEdges = 40
+
+// Mermaid renders the roots and their direct requirements as a Mermaid
+// flowchart with a subgraph per ecosystem, listing at most mermaidMaxEdges
+// requirements per ecosystem. The full graph is left to DOT, GraphML and
+// JSON.
+func Mermaid(g *models.DependencyGraph) string {
+    ids := make(map[string]string, len(g.Nodes))
+    for i, n := range g.Nodes {
+        ids[n.ID] = fmt.Sprintf("n%d", i)
+    }
+
+    var sb strings.Builder
+    sb.WriteString("graph TD\n")
+    for _, ecosystem := range g.Ecosystems() {
+        var edges []models.DependencyEdge
+        hidden := 0
+        for _, e := range g.Edges {
+            from := g.Node(e.From)
+            if from == nil || from.Ecosystem != ecosystem || !from.Root || !e.Direct {
+                continue
+            }
+            if len(edges) == mermaidMaxEdges {
+                hidden++
+                continue
+            }
+            edges = append(edges, e)
+        }
+
+        shown := make(map[string]bool)
+        for _, e := range edges {
+            shown[e.From] = true
+            shown[e.To] = true
+        }
+
+        fmt.Fprintf(&sb, "    subgraph %s [\"%s\"]\n", ecosystem, mermaidEscape(EcosystemTitle(ecosystem)))
+        for _, n := range g.Nodes {
+            if n.Ecosystem != ecosystem || !(n.Root || shown[n.ID]) {
+                continue
+            }
+            if n.Root {
+                fmt.Fprintf(&sb, "        %s[[\"%s\"]]\n", ids[n.ID], mermaidEscape(Label(n)))
+            } else {
+                fmt.Fprintf(&sb, "        %s[\"%s\"]\n", ids[n.ID], mermaidEscape(Label(n)))
+            }
+        }
+        for _, e := range edges {
+            arrow := "-->"
+            if label := edgeLabel(e); label != "" {
+                arrow += "|" + mermaidEscape(label) + "|"
+            }
+            fmt.Fprintf(&sb, "        %s %s %s\n", ids[e.From], arrow, ids[e.To])
+        }
+        if hidden > 0 {
+            fmt.Fprintf(&sb, "        %s_more[\"%d more\"]\n", ecosystem, hidden)
+        }
+        sb.WriteString("    end\n")
+    }
+    return sb.String()
+}
+
+func mermaidEscape(s string) string {
+    return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// WriteDOT writes the graph in Graphviz DOT. Roots are bold and indirect
+// edges dashed.
+func WriteDOT(w io.Writer, g *models.DependencyGraph) error {
+    var sb strings.Builder
+    sb.WriteString("digraph dependencies {\n")
+    sb.WriteString("    rankdir=LR;\n")
+    sb.WriteString("    node [shape=box];\n")
+    for _, ecosystem := range g.Ecosystems() {
+        fmt.Fprintf(&sb, "    subgraph %s {\n", dotQuote("cluster_"+ecosystem))
+        fmt.Fprintf(&sb, "        label=%s;\n", dotQuote(EcosystemTitle(ecosystem)))
+        for _, n := range g.Nodes {
+            if n.Ecosystem != ecosystem {
+                continue
+            }
+            attrs := []string{"label=" + dotQuote(Label(n))}
+            if n.Root {
+                attrs = append(attrs, "style=bold")
+            }
+            fmt.Fprintf(&sb, "        %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+        }
+        sb.WriteString("    }\n")
+    }
+    for _, e := range g.Edges {
+        var attrs []string
+        if !e.Direct {
+            attrs = append(attrs, "style=dashed")
+        }
+        if label := edgeLabel(e); label != "" {
+            attrs = append(attrs, "label="+dotQuote(label))
+        }
+        if len(attrs) > 0 {
+            fmt.Fprintf(&sb, "    %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), strings.Join(attrs, ", "))
+        } else {
+            fmt.Fprintf(&sb, "    %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+        }
+    }
+    sb.WriteString("}\n")
+
+    _, err := io.WriteString(w, sb.String())
+    return err
+}
+
+func dotQuote(s string) string {
+    s = strings.ReplaceAll(s, `\`, `\\`)
+    return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// WriteJSON writes the graph as indented JSON.
+func WriteJSON(w io.Writer, g *models.DependencyGraph) error {
+    data, err := json.MarshalIndent(g, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to encode dependency graph: %v", err)
+    }
+    _, err = w.Write(append(data, '\n'))
+    return err
+}
+
+type graphML struct {
+    XMLName xml.Name     `xml:"graphml"`
+    XMLNS   string       `xml:"xmlns,attr"`
+    Keys    []graphMLKey `xml:"key"`
+    Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+    ID   string `xml:"id,attr"`
+    For  string `xml:"for,attr"`
+    Name string `xml:"attr.name,attr"`
+    Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+    ID          string        `xml:"id,attr"`
+    EdgeDefault string        `xml:"edgedefault,attr"`
+    Nodes       []graphMLNode `xml:"node"`
+    Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+    ID   string        `xml:"id,attr"`
+    Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+    Source string        `xml:"source,attr"`
+    Target string        `xml:"target,attr"`
+    Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+    Key   string `xml:"key,attr"`
+    Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as GraphML, with the node and edge fields
+// as data attributes.
+func WriteGraphML(w io.Writer, g *models.DependencyGraph) error {
+    doc := graphML{
+        XMLNS: "http://graphml.graphdrawing.org/xmlns",
+        Keys: []graphMLKey{
+            {ID: "name", For: "node", Name: "name", Type: "string"},
+            {ID: "version", For: "node", Name: "version", Type: "string"},
+            {ID: "ecosystem", For: "node", Name: "ecosystem", Type: "string"},
+            {ID: "root", For: "node", Name: "root", Type: "boolean"},
+            {ID: "replacement", For: "node", Name: "replacement", Type: "string"},
+            {ID: "direct", For: "edge", Name: "direct", Type: "boolean"},
+            {ID: "scope", For: "edge", Name: "scope", Type: "string"},
+        },
+        Graph: graphMLGraph{ID: "dependencies", EdgeDefault: "directed"},
+    }
+    for _, n := range g.Nodes {
+        node := graphMLNode{ID: n.ID, Data: []graphMLData{
+            {Key: "name", Value: n.Name},
+            {Key: "ecosystem", Value: n.Ecosystem},
+            {Key: "root", Value: fmt.Sprint(n.Root)},
+        }}
+        if n.Version != "" {
+            node.Data = append(node.Data, graphMLData{Key: "version", Value: n.Version})
+        }
+        if n.Replacement != "" {
+            node.Data = append(node.Data, graphMLData{Key: "replacement", Value: n.Replacement})
+        }
+        doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+    }
+    for _, e := range g.Edges {
+        edge := graphMLEdge{Source: e.From, Target: e.To, Data: []graphMLData{
+            {Key: "direct", Value: fmt.Sprint(e.Direct)},
+        }}
+        if e.Scope != "" {
+            edge.Data = append(edge.Data, graphMLData{Key: "scope", Value: e.Scope})
+        }
+        doc.Graph.Edges = append(doc.Graph.Edges, edge)
+    }
+
+    data, err := xml.MarshalIndent(doc, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to encode dependency graph: %v", err)
+    }
+    if _, err := io.WriteString(w, xml.Header); err != nil {
+        return err
+    }
+    _, err = w.Write(append(data, '\n'))
+    return err
+}
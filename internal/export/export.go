@@ -0,0 +1,139 @@
+// Package export writes analysis reports in the registered formats and
+// stores them in an artifact sink, a local directory or an S3-compatible
+// object store.
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/report"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+// Options carries what formats need besides the result.
+type Options struct {
+    // RepoPath is read for source snippets by the HTML and PDF formats.
+    RepoPath string
+    Renderer *report.Renderer
+}
+
+// Format renders a result to a file.
+type Format struct {
+    Name        string
+    Extension   string // appended to "code-review-<id>-<token>", e.g. ".junit.xml"
+    ContentType string
+    Write       func(result *models.AnalysisResult, opts Options, path string) error
+}
+
+var (
+    registryMu sync.RWMutex
+    registry   = make(map[string]Format)
+)
+
+// Register adds a format, replacing any format with the same name.
+func Register(format Format) {
+    registryMu.Lock()
+    defer registryMu.Unlock()
+    registry[format.Name] = format
+}
+
+// Lookup returns the format registered under name.
+func Lookup(name string) (Format, bool) {
+    registryMu.RLock()
+    defer registryMu.RUnlock()
+    format, ok := registry[name]
+    return format, ok
+}
+
+// Names returns the registered format names, sorted.
+func Names() []string {
+    registryMu.RLock()
+    defer registryMu.RUnlock()
+    names := make([]string, 0, len(registry))
+    for name := range registry {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// Validate checks that every configured format is registered and that the
+// sink can be created.
+func Validate(cfg models.ExportConfig) error {
+    for _, name := range cfg.Formats {
+        if _, ok := Lookup(name); !ok {
+            return fmt.Errorf("unknown export format %q (available: %v)", name, Names())
+        }
+    }
+    _, err := NewSink(cfg, "")
+    return err
+}
+
+// Exporter renders results and stores the reports in a sink.
+type Exporter struct {
+    sink Sink
+    opts Options
+}
+
+func NewExporter(sink Sink, opts Options) *Exporter {
+    return &Exporter{sink: sink, opts: opts}
+}
+
+// Export writes the result in each format and returns the stored reports.
+// Formats are rendered into a staging directory first so a sink only ever
+// sees complete files. A failing format is logged and skipped.
+func (e *Exporter) Export(ctx context.Context, result *models.AnalysisResult, formats []string) []models.OutputFile {
+    if len(formats) == 0 {
+        return nil
+    }
+
+    staging, err := os.MkdirTemp("", "gollora-export-")
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to create export staging directory: %v", err)
+        return nil
+    }
+    defer os.RemoveAll(staging)
+
+    // The random token keeps report names unguessable from the result ID.
+    token, err := randomToken(8)
+    if err != nil {
+        utils.LogWithLocation(utils.Error, "Failed to generate report name: %v", err)
+        return nil
+    }
+
+    var files []models.OutputFile
+    for _, name := range formats {
+        format, ok := Lookup(name)
+        if !ok {
+            utils.LogWithLocation(utils.Warn, "Skipping unknown export format: %s", name)
+            continue
+        }
+
+        fileName := fmt.Sprintf("code-review-%s-%s%s", result.ID, token, format.Extension)
+        staged := filepath.Join(staging, fileName)
+        if err := format.Write(result, e.opts, staged); err != nil {
+            utils.LogWithLocation(utils.Error, "Failed to export results to %s: %v", name, err)
+            continue
+        }
+
+        location, url, err := e.sink.Put(ctx, fileName, staged, format.ContentType)
+        if err != nil {
+            utils.LogWithLocation(utils.Error, "Failed to store %s report: %v", name, err)
+            continue
+        }
+        utils.LogWithLocation(utils.Info, "Exported %s report to %s", name, location)
+
+        files = append(files, models.OutputFile{
+            Format: name,
+            Path:   location,
+            URL:    url,
+        })
+    }
+    return files
+}
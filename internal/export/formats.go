@@ -0,0 +1,116 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/euclidstellar/gollora/internal/depgraph"
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/euclidstellar/gollora/internal/utils"
+)
+
+func init() {
+    Register(Format{
+        Name:        "json",
+        Extension:   ".json",
+        ContentType: "application/json",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToJSON(result, path)
+        },
+    })
+    Register(Format{
+        Name:        "ndjson",
+        Extension:   ".ndjson",
+        ContentType: "application/x-ndjson",
+        Write:       writeNDJSON,
+    })
+    Register(Format{
+        Name:        "markdown",
+        Extension:   ".md",
+        ContentType: "text/markdown; charset=utf-8",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            _, err := utils.FormatToMarkdown(result, opts.Renderer, path)
+            return err
+        },
+    })
+    Register(Format{
+        Name:        "pdf",
+        Extension:   ".pdf",
+        ContentType: "application/pdf",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToPDF(result, opts.RepoPath, path)
+        },
+    })
+    Register(Format{
+        Name:        "html",
+        Extension:   ".html",
+        ContentType: "text/html; charset=utf-8",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToHTML(result, opts.RepoPath, path)
+        },
+    })
+    Register(Format{
+        Name:        "sarif",
+        Extension:   ".sarif",
+        ContentType: "application/sarif+json",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToSARIF(result, path)
+        },
+    })
+    Register(Format{
+        Name:        "junit",
+        Extension:   ".junit.xml",
+        ContentType: "application/xml",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToJUnit(result, path)
+        },
+    })
+    Register(Format{
+        Name:        "codeclimate",
+        Extension:   ".codeclimate.json",
+        ContentType: "application/json",
+        Write: func(result *models.AnalysisResult, opts Options, path string) error {
+            return utils.FormatToCodeClimate(result, path)
+        },
+    })
+    Register(Format{
+        Name:        "dot",
+        Extension:   ".deps.dot",
+        ContentType: "text/vnd.graphviz",
+        Write:       writeDependencies(depgraph.WriteDOT),
+    })
+    Register(Format{
+        Name:        "graphml",
+        Extension:   ".deps.graphml",
+        ContentType: "application/xml",
+        Write:       writeDependencies(depgraph.WriteGraphML),
+    })
+    Register(Format{
+        Name:        "deps-json",
+        Extension:   ".deps.json",
+        ContentType: "application/json",
+        Write:       writeDependencies(depgraph.WriteJSON),
+    })
+}
+
+// writeDependencies adapts a dependency graph writer to a format. Results
+// without a graph fail the format rather than writing an empty file.
+func writeDependencies(render func(io.Writer, *models.DependencyGraph) error) func(*models.AnalysisResult, Options, string) error {
+    return func(result *models.AnalysisResult, opts Options, path string) error {
+        if result.Dependencies.Empty() {
+            return fmt.Errorf("no dependency graph was built for this analysis")
+        }
+
+        f, err := os.Create(path)
+        if err != nil {
+            return fmt.Errorf("failed to create dependency graph file: %v", err)
+        }
+        defer f.Close()
+
+        if err := render(f, result.Dependencies); err != nil {
+            return err
+        }
+        return f.Close()
+    }
+}
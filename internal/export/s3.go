@@ -0,0 +1,169 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// S3Sink uploads reports to an S3-compatible object store with path-style
+// PUT requests signed with AWS Signature Version 4. Without credentials the
+// requests are sent unsigned, which is enough for a local stand-in store.
+type S3Sink struct {
+    endpoint  string
+    region    string
+    bucket    string
+    prefix    string
+    accessKey string
+    secretKey string
+    publicURL string
+    client    *http.Client
+    now       func() time.Time
+}
+
+func NewS3Sink(cfg models.S3Config) (*S3Sink, error) {
+    if cfg.Bucket == "" {
+        return nil, fmt.Errorf("export.s3.bucket is required for the s3 sink")
+    }
+    endpoint := strings.TrimRight(cfg.Endpoint, "/")
+    if endpoint == "" {
+        endpoint = "https://s3.amazonaws.com"
+    }
+    if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+        return nil, fmt.Errorf("export.s3.endpoint must be an http(s) URL, got %q", cfg.Endpoint)
+    }
+    region := cfg.Region
+    if region == "" {
+        region = "us-east-1"
+    }
+    prefix := strings.Trim(cfg.Prefix, "/")
+    if prefix != "" {
+        prefix += "/"
+    }
+
+    return &S3Sink{
+        endpoint:  endpoint,
+        region:    region,
+        bucket:    cfg.Bucket,
+        prefix:    prefix,
+        accessKey: cfg.AccessKey,
+        secretKey: cfg.SecretKey,
+        publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+        client:    &http.Client{Timeout: 60 * time.Second},
+        now:       time.Now,
+    }, nil
+}
+
+func (s *S3Sink) Put(ctx context.Context, name, path, contentType string) (string, string, error) {
+    body, err := os.ReadFile(path)
+    if err != nil {
+        return "", "", fmt.Errorf("failed to read %s: %v", path, err)
+    }
+
+    key := s.prefix + name
+    objectURL := s.endpoint + "/" + awsEscape(s.bucket, false) + "/" + awsEscape(key, true)
+    req, err := http.NewRequestWithContext(ctx, "PUT", objectURL, bytes.NewReader(body))
+    if err != nil {
+        return "", "", fmt.Errorf("failed to create upload request: %v", err)
+    }
+    req.Header.Set("Content-Type", contentType)
+    if s.accessKey != "" {
+        s.sign(req, body)
+    }
+
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return "", "", fmt.Errorf("failed to upload %s: %v", key, err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 300 {
+        respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+        return "", "", fmt.Errorf("object store returned %s for %s: %s", resp.Status, key, strings.TrimSpace(string(respBody)))
+    }
+
+    link := objectURL
+    if s.publicURL != "" {
+        link = s.publicURL + "/" + awsEscape(key, true)
+    }
+    return fmt.Sprintf("s3://%s/%s", s.bucket, key), link, nil
+}
+
+// sign adds the SigV4 headers for a single-chunk payload.
+func (s *S3Sink) sign(req *http.Request, body []byte) {
+    now := s.now().UTC()
+    amzDate := now.Format("20060102T150405Z")
+    date := now.Format("20060102")
+    payloadHash := sha256Hex(body)
+
+    req.Header.Set("X-Amz-Date", amzDate)
+    req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+    signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+    canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+        strings.TrimSpace(req.Header.Get("Content-Type")), req.URL.Host, payloadHash, amzDate)
+    canonicalRequest := strings.Join([]string{
+        req.Method,
+        req.URL.EscapedPath(),
+        req.URL.RawQuery,
+        canonicalHeaders,
+        signedHeaders,
+        payloadHash,
+    }, "\n")
+
+    scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+    stringToSign := strings.Join([]string{
+        "AWS4-HMAC-SHA256",
+        amzDate,
+        scope,
+        sha256Hex([]byte(canonicalRequest)),
+    }, "\n")
+
+    key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+    key = hmacSHA256(key, s.region)
+    key = hmacSHA256(key, "s3")
+    key = hmacSHA256(key, "aws4_request")
+    signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+    req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+        s.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+    sum := sha256.Sum256(data)
+    return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+    mac := hmac.New(sha256.New, key)
+    mac.Write([]byte(data))
+    return mac.Sum(nil)
+}
+
+// awsEscape percent-encodes everything but unreserved characters, as SigV4
+// requires, keeping "/" when keepSlash is set.
+func awsEscape(s string, keepSlash bool) string {
+    var sb strings.Builder
+    for _, b := range []byte(s) {
+        switch {
+        case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+            b == '-', b == '_', b == '.', b == '~':
+            sb.WriteByte(b)
+        case b == '/' && keepSlash:
+            sb.WriteByte(b)
+        default:
+            fmt.Fprintf(&sb, "%%%02X", b)
+        }
+    }
+    return sb.String()
+}
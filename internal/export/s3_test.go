@@ -0,0 +1,181 @@
+package export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// s3Request is what the stand-in object store saw.
+type s3Request struct {
+    method string
+    path   string
+    header http.Header
+    body   string
+    // signature is the SigV4 signature recomputed from the request as
+    // received, with the test's secret key.
+    signature string
+}
+
+func newTestS3Server(t *testing.T, secretKey string, status int) (*httptest.Server, *s3Request) {
+    t.Helper()
+    got := &s3Request{}
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, _ := io.ReadAll(r.Body)
+        got.method = r.Method
+        got.path = r.URL.EscapedPath()
+        got.header = r.Header.Clone()
+        got.body = string(body)
+        if r.Header.Get("Authorization") != "" {
+            got.signature = expectedSigV4(r, secretKey)
+        }
+        w.WriteHeader(status)
+        if status >= 300 {
+            io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>")
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server, got
+}
+
+// expectedSigV4 follows the steps in the AWS Signature Version 4 docs for
+// the headers the sink signs.
+func expectedSigV4(r *http.Request, secretKey string) string {
+    amzDate := r.Header.Get("X-Amz-Date")
+    date := amzDate[:8]
+    region := strings.Split(strings.Split(r.Header.Get("Authorization"), "Credential=")[1], "/")[2]
+
+    canonical := r.Method + "\n" +
+        r.URL.EscapedPath() + "\n" +
+        r.URL.RawQuery + "\n" +
+        "content-type:" + r.Header.Get("Content-Type") + "\n" +
+        "host:" + r.Host + "\n" +
+        "x-amz-content-sha256:" + r.Header.Get("X-Amz-Content-Sha256") + "\n" +
+        "x-amz-date:" + amzDate + "\n" +
+        "\n" +
+        "content-type;host;x-amz-content-sha256;x-amz-date\n" +
+        r.Header.Get("X-Amz-Content-Sha256")
+    canonicalHash := sha256.Sum256([]byte(canonical))
+    stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" +
+        date + "/" + region + "/s3/aws4_request\n" + hex.EncodeToString(canonicalHash[:])
+
+    mac := func(key []byte, data string) []byte {
+        h := hmac.New(sha256.New, key)
+        h.Write([]byte(data))
+        return h.Sum(nil)
+    }
+    key := mac([]byte("AWS4"+secretKey), date)
+    key = mac(key, region)
+    key = mac(key, "s3")
+    key = mac(key, "aws4_request")
+    return hex.EncodeToString(mac(key, stringToSign))
+}
+
+func writeReport(t *testing.T, content string) string {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "report.html")
+    if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+        t.Fatalf("failed to write report: %v", err)
+    }
+    return path
+}
+
+func TestS3SinkPutSignsRequest(t *testing.T) {
+    server, got := newTestS3Server(t, "secret", http.StatusOK)
+    sink, err := NewS3Sink(models.S3Config{
+        Endpoint:  server.URL,
+        Region:    "eu-west-1",
+        Bucket:    "reports",
+        Prefix:    "/gollora/",
+        AccessKey: "AKIDEXAMPLE",
+        SecretKey: "secret",
+    })
+    if err != nil {
+        t.Fatalf("NewS3Sink failed: %v", err)
+    }
+    sink.now = func() time.Time { return time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC) }
+
+    content := "<html>report</html>"
+    location, link, err := sink.Put(context.Background(), "code review.html", writeReport(t, content), "text/html")
+    if err != nil {
+        t.Fatalf("Put failed: %v", err)
+    }
+
+    if got.method != http.MethodPut {
+        t.Errorf("method = %s, want PUT", got.method)
+    }
+    if want := "/reports/gollora/code%20review.html"; got.path != want {
+        t.Errorf("path = %s, want %s", got.path, want)
+    }
+    if got.body != content {
+        t.Errorf("body = %q, want %q", got.body, content)
+    }
+    if want := "20261018T123000Z"; got.header.Get("X-Amz-Date") != want {
+        t.Errorf("X-Amz-Date = %s, want %s", got.header.Get("X-Amz-Date"), want)
+    }
+    sum := sha256.Sum256([]byte(content))
+    if want := hex.EncodeToString(sum[:]); got.header.Get("X-Amz-Content-Sha256") != want {
+        t.Errorf("X-Amz-Content-Sha256 = %s, want %s", got.header.Get("X-Amz-Content-Sha256"), want)
+    }
+
+    wantAuth := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20261018/eu-west-1/s3/aws4_request, "+
+        "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=%s", got.signature)
+    if auth := got.header.Get("Authorization"); auth != wantAuth {
+        t.Errorf("Authorization = %s\nwant %s", auth, wantAuth)
+    }
+
+    if want := "s3://reports/gollora/code review.html"; location != want {
+        t.Errorf("location = %s, want %s", location, want)
+    }
+    if want := server.URL + "/reports/gollora/code%20review.html"; link != want {
+        t.Errorf("link = %s, want %s", link, want)
+    }
+}
+
+func TestS3SinkPutPublicURL(t *testing.T) {
+    server, got := newTestS3Server(t, "", http.StatusOK)
+    sink, err := NewS3Sink(models.S3Config{
+        Endpoint:  server.URL,
+        Bucket:    "reports",
+        PublicURL: "https://cdn.example.com/",
+    })
+    if err != nil {
+        t.Fatalf("NewS3Sink failed: %v", err)
+    }
+
+    _, link, err := sink.Put(context.Background(), "report.json", writeReport(t, "{}"), "application/json")
+    if err != nil {
+        t.Fatalf("Put failed: %v", err)
+    }
+    if auth := got.header.Get("Authorization"); auth != "" {
+        t.Errorf("unsigned sink sent Authorization %q", auth)
+    }
+    if want := "https://cdn.example.com/report.json"; link != want {
+        t.Errorf("link = %s, want %s", link, want)
+    }
+}
+
+func TestS3SinkPutError(t *testing.T) {
+    server, _ := newTestS3Server(t, "", http.StatusForbidden)
+    sink, err := NewS3Sink(models.S3Config{Endpoint: server.URL, Bucket: "reports"})
+    if err != nil {
+        t.Fatalf("NewS3Sink failed: %v", err)
+    }
+
+    _, _, err = sink.Put(context.Background(), "report.json", writeReport(t, "{}"), "application/json")
+    if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+        t.Errorf("err = %v, want the store's AccessDenied reply", err)
+    }
+}
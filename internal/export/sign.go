@@ -0,0 +1,55 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultLinkTTL is how long signed report links stay valid when
+// export.link_ttl_hours isn't set.
+const DefaultLinkTTL = 72 * time.Hour
+
+// SignReportLink returns the query that grants access to the report called
+// name until expires.
+func SignReportLink(key, name string, expires time.Time) url.Values {
+    unix := strconv.FormatInt(expires.Unix(), 10)
+    return url.Values{
+        "expires":   {unix},
+        "signature": {reportSignature(key, name, unix)},
+    }
+}
+
+// VerifyReportLink reports whether query carries an unexpired signature for
+// name. Without a key no link is valid.
+func VerifyReportLink(key, name string, query url.Values, now time.Time) bool {
+    if key == "" {
+        return false
+    }
+    unix := query.Get("expires")
+    expires, err := strconv.ParseInt(unix, 10, 64)
+    if err != nil || now.Unix() > expires {
+        return false
+    }
+    want := reportSignature(key, name, unix)
+    return hmac.Equal([]byte(query.Get("signature")), []byte(want))
+}
+
+func reportSignature(key, name, expires string) string {
+    mac := hmac.New(sha256.New, []byte(key))
+    mac.Write([]byte(name + "\n" + expires))
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomToken returns n random bytes, hex encoded.
+func randomToken(n int) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
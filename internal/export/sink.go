@@ -0,0 +1,115 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// DefaultOutputDir is where the local sink writes when neither the request
+// nor the config names a directory.
+const DefaultOutputDir = "code-review-output"
+
+// Sink stores exported report files.
+type Sink interface {
+    // Put stores the file at path under name and returns where it was
+    // stored and the URL it can be fetched from.
+    Put(ctx context.Context, name, path, contentType string) (location, url string, err error)
+}
+
+// NewSink creates the sink selected by cfg. outputDir, when set, overrides
+// cfg.OutputDir for the local sink.
+func NewSink(cfg models.ExportConfig, outputDir string) (Sink, error) {
+    switch cfg.Sink {
+    case "", "local":
+        if outputDir == "" {
+            outputDir = cfg.OutputDir
+        }
+        if outputDir == "" {
+            outputDir = DefaultOutputDir
+        }
+        if cfg.BaseURL != "" && cfg.SigningKey == "" {
+            return nil, fmt.Errorf("export.signing_key is required with export.base_url: /reports/ only serves signed links")
+        }
+        sink := NewLocalSink(outputDir, cfg.BaseURL)
+        sink.SigningKey = cfg.SigningKey
+        if cfg.LinkTTLHours > 0 {
+            sink.LinkTTL = time.Duration(cfg.LinkTTLHours) * time.Hour
+        }
+        return sink, nil
+    case "s3":
+        return NewS3Sink(cfg.S3)
+    default:
+        return nil, fmt.Errorf("unknown export sink %q", cfg.Sink)
+    }
+}
+
+// LocalSink copies reports into a directory.
+type LocalSink struct {
+    Dir string
+    // BaseURL is the public URL Dir is served at. Without it reports get
+    // file:// URLs.
+    BaseURL string
+    // SigningKey, when set, signs BaseURL links to expire after LinkTTL.
+    SigningKey string
+    LinkTTL    time.Duration
+    now        func() time.Time
+}
+
+func NewLocalSink(dir, baseURL string) *LocalSink {
+    return &LocalSink{
+        Dir:     dir,
+        BaseURL: strings.TrimRight(baseURL, "/"),
+        LinkTTL: DefaultLinkTTL,
+        now:     time.Now,
+    }
+}
+
+func (s *LocalSink) Put(ctx context.Context, name, path, contentType string) (string, string, error) {
+    if err := os.MkdirAll(s.Dir, 0755); err != nil {
+        return "", "", fmt.Errorf("failed to create output directory: %v", err)
+    }
+
+    dest := filepath.Join(s.Dir, name)
+    if err := copyFile(path, dest); err != nil {
+        return "", "", err
+    }
+
+    if s.BaseURL != "" {
+        link := s.BaseURL + "/" + url.PathEscape(name)
+        if s.SigningKey != "" {
+            link += "?" + SignReportLink(s.SigningKey, name, s.now().Add(s.LinkTTL)).Encode()
+        }
+        return dest, link, nil
+    }
+    abs, err := filepath.Abs(dest)
+    if err != nil {
+        return dest, "", nil
+    }
+    return dest, (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+func copyFile(src, dest string) error {
+    in, err := os.Open(src)
+    if err != nil {
+        return fmt.Errorf("failed to open %s: %v", src, err)
+    }
+    defer in.Close()
+
+    out, err := os.Create(dest)
+    if err != nil {
+        return fmt.Errorf("failed to create %s: %v", dest, err)
+    }
+    if _, err := io.Copy(out, in); err != nil {
+        out.Close()
+        return fmt.Errorf("failed to write %s: %v", dest, err)
+    }
+    return out.Close()
+}
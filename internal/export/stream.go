@@ -0,0 +1,109 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// Stream receives a result one finding at a time, for consumers that
+// shouldn't wait for a large scan to be marshalled in one piece.
+type Stream interface {
+    // Issue is called once per issue of the aggregated result, after
+    // duplicates are merged and severities scored, so the streamed issues
+    // are the ones the summary counts.
+    Issue(issue models.CodeIssue) error
+    // Finish is called once, after the last issue, with the completed result.
+    Finish(result *models.AnalysisResult) error
+}
+
+// SummaryRecord is the last line of an NDJSON report. Its "record" field
+// tells it apart from the CodeIssue lines before it.
+type SummaryRecord struct {
+    Record       string                   `json:"record"`
+    ID           string                   `json:"id"`
+    Event        models.WebhookEvent      `json:"event"`
+    Summary      models.Summary           `json:"summary"`
+    AnalyzedAt   time.Time                `json:"analyzed_at"`
+    CompletedAt  time.Time                `json:"completed_at"`
+    Duration     float64                  `json:"duration_seconds"`
+    QualityGate  *models.GateVerdict      `json:"quality_gate,omitempty"`
+    Risk         *models.RiskScore        `json:"risk,omitempty"`
+    History      *models.LifecycleSummary `json:"history,omitempty"`
+    Dependencies *models.DependencyGraph  `json:"dependencies,omitempty"`
+    OutputFiles  []models.OutputFile      `json:"output_files,omitempty"`
+}
+
+// NewSummaryRecord returns everything in result but its issues.
+func NewSummaryRecord(result *models.AnalysisResult) SummaryRecord {
+    return SummaryRecord{
+        Record:       "summary",
+        ID:           result.ID,
+        Event:        result.Event,
+        Summary:      result.Summary,
+        AnalyzedAt:   result.AnalyzedAt,
+        CompletedAt:  result.CompletedAt,
+        Duration:     result.Duration,
+        QualityGate:  result.QualityGate,
+        Risk:         result.Risk,
+        History:      result.History,
+        Dependencies: result.Dependencies,
+        OutputFiles:  result.OutputFiles,
+    }
+}
+
+// NDJSONStream writes one CodeIssue per line as findings are streamed, then
+// a SummaryRecord. Issues below threshold are skipped unless suppressed, as
+// FilterBySeverity does, so the summary counts the same issues.
+type NDJSONStream struct {
+    enc       *json.Encoder
+    threshold models.Threshold
+}
+
+func NewNDJSONStream(w io.Writer, threshold models.Threshold) *NDJSONStream {
+    enc := json.NewEncoder(w)
+    enc.SetEscapeHTML(false)
+    return &NDJSONStream{enc: enc, threshold: threshold}
+}
+
+func (s *NDJSONStream) Issue(issue models.CodeIssue) error {
+    if !s.threshold.Keeps(issue) {
+        return nil
+    }
+    if err := s.enc.Encode(issue); err != nil {
+        return fmt.Errorf("failed to write NDJSON issue: %v", err)
+    }
+    return nil
+}
+
+func (s *NDJSONStream) Finish(result *models.AnalysisResult) error {
+    if err := s.enc.Encode(NewSummaryRecord(result.FilterBySeverity(s.threshold))); err != nil {
+        return fmt.Errorf("failed to write NDJSON summary: %v", err)
+    }
+    return nil
+}
+
+// writeNDJSON is the "ndjson" export format: the finished result written
+// the same way a stream would have.
+func writeNDJSON(result *models.AnalysisResult, opts Options, path string) error {
+    f, err := os.Create(path)
+    if err != nil {
+        return fmt.Errorf("failed to create NDJSON file: %v", err)
+    }
+    defer f.Close()
+
+    stream := NewNDJSONStream(f, models.Threshold{})
+    for _, issue := range result.Issues {
+        if err := stream.Issue(issue); err != nil {
+            return err
+        }
+    }
+    if err := stream.Finish(result); err != nil {
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,36 @@
+//go:build !unix
+
+package history
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+    lockRetryInterval = 50 * time.Millisecond
+    // staleLockAge is how old a lock file must be before it is taken to be
+    // left behind by a run that crashed.
+    staleLockAge = 2 * time.Minute
+)
+
+// lockFile blocks until it creates path exclusively, and removes it again
+// on unlock.
+func lockFile(path string) (func(), error) {
+    for {
+        f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+        if err == nil {
+            f.Close()
+            return func() { os.Remove(path) }, nil
+        }
+        if !os.IsExist(err) {
+            return nil, fmt.Errorf("failed to lock history database: %v", err)
+        }
+        if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+            os.Remove(path)
+            continue
+        }
+        time.Sleep(lockRetryInterval)
+    }
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package history
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile blocks until it holds an exclusive lock on path, creating the
+// file if needed. The kernel drops the lock if the process dies.
+func lockFile(path string) (func(), error) {
+    f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open history lock: %v", err)
+    }
+    if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+        f.Close()
+        return nil, fmt.Errorf("failed to lock history database: %v", err)
+    }
+    return func() {
+        syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+        f.Close()
+    }, nil
+}
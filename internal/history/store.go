@@ -0,0 +1,454 @@
+// Package history persists analyses and fingerprinted issues per repository
+// and branch so each issue can be tracked as new, persisting, fixed or
+// reintroduced across the commits of a pull request and across branches.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+const (
+    databaseVersion    = 1
+    defaultMaxAnalyses = 200
+    // maxFixedRecords caps the fixed issues kept per branch; they are only
+    // needed to tell reintroduced issues from new ones.
+    maxFixedRecords = 1000
+)
+
+// Run describes one analysis being recorded.
+type Run struct {
+    Repo          string
+    Branch        string
+    BaseBranch    string
+    Commit        string
+    AnalysisID    string
+    PullRequestID int
+    // Files lists the analyzed files. Only issues in these files can be
+    // marked fixed, since the rest of the repository was not looked at.
+    Files []string
+    At    time.Time
+}
+
+type analysisRecord struct {
+    ID            string    `json:"id"`
+    Repo          string    `json:"repo"`
+    Branch        string    `json:"branch"`
+    Commit        string    `json:"commit"`
+    PullRequestID int       `json:"pull_request_id,omitempty"`
+    AnalyzedAt    time.Time `json:"analyzed_at"`
+    IssueCount    int       `json:"issue_count"`
+}
+
+type database struct {
+    Version  int                   `json:"version"`
+    Analyses []analysisRecord      `json:"analyses"`
+    Issues   []*models.IssueRecord `json:"issues"`
+}
+
+// Store is a single-file issue history database. Writes replace the file
+// atomically, so a crash never leaves a partially written database, and
+// each update holds a lock on a ".lock" file beside it, so analyses in
+// other processes sharing the database don't lose each other's updates.
+type Store struct {
+    path        string
+    maxAnalyses int
+    mu          sync.Mutex
+}
+
+var (
+    openMu     sync.Mutex
+    openStores = make(map[string]*Store)
+)
+
+// Open returns the store for the database file at path, creating its
+// directory if needed. Stores are shared per path so concurrent analyses in
+// one process serialize their updates.
+func Open(path string, maxAnalyses int) (*Store, error) {
+    abs, err := filepath.Abs(path)
+    if err != nil {
+        return nil, fmt.Errorf("invalid history path %q: %v", path, err)
+    }
+    if maxAnalyses <= 0 {
+        maxAnalyses = defaultMaxAnalyses
+    }
+
+    openMu.Lock()
+    defer openMu.Unlock()
+
+    if s, ok := openStores[abs]; ok {
+        return s, nil
+    }
+    if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+        return nil, fmt.Errorf("failed to create history directory: %v", err)
+    }
+
+    s := &Store{path: abs, maxAnalyses: maxAnalyses}
+    openStores[abs] = s
+    return s, nil
+}
+
+func (s *Store) load() (*database, error) {
+    data, err := os.ReadFile(s.path)
+    if os.IsNotExist(err) {
+        return &database{Version: databaseVersion}, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read history database: %v", err)
+    }
+
+    var db database
+    if err := json.Unmarshal(data, &db); err != nil {
+        return nil, fmt.Errorf("failed to parse history database: %v", err)
+    }
+    if db.Version > databaseVersion {
+        return nil, fmt.Errorf("history database version %d is newer than supported version %d", db.Version, databaseVersion)
+    }
+    return &db, nil
+}
+
+func (s *Store) save(db *database) error {
+    data, err := json.Marshal(db)
+    if err != nil {
+        return fmt.Errorf("failed to encode history database: %v", err)
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(s.path), ".history-*")
+    if err != nil {
+        return fmt.Errorf("failed to write history database: %v", err)
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return fmt.Errorf("failed to write history database: %v", err)
+    }
+    if err := tmp.Close(); err != nil {
+        return fmt.Errorf("failed to write history database: %v", err)
+    }
+    if err := os.Rename(tmp.Name(), s.path); err != nil {
+        return fmt.Errorf("failed to replace history database: %v", err)
+    }
+    return nil
+}
+
+func recordKey(repo, branch, fingerprint string) string {
+    return repo + "\x00" + branch + "\x00" + fingerprint
+}
+
+func indexIssues(db *database) map[string]*models.IssueRecord {
+    index := make(map[string]*models.IssueRecord, len(db.Issues))
+    for _, rec := range db.Issues {
+        index[recordKey(rec.Repo, rec.Branch, rec.Fingerprint)] = rec
+    }
+    return index
+}
+
+// Record stores an analysis, sets the lifecycle fields of issues in place
+// and returns the lifecycle summary for the run's branch.
+//
+// With a base branch, an issue is persisting when the base has it open,
+// reintroduced when the base recorded it as fixed, and otherwise keeps the
+// status it got when the branch introduced it. Without one, issues seen in
+// an earlier analysis of the branch are persisting. Open issues in the
+// analyzed files that no longer appear are marked fixed, including issues
+// inherited from the base branch.
+func (s *Store) Record(run Run, issues []models.CodeIssue) (*models.LifecycleSummary, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    unlock, err := lockFile(s.path + ".lock")
+    if err != nil {
+        return nil, err
+    }
+    defer unlock()
+
+    db, err := s.load()
+    if err != nil {
+        return nil, err
+    }
+    index := indexIssues(db)
+
+    if run.At.IsZero() {
+        run.At = time.Now()
+    }
+    hasBase := run.BaseBranch != "" && run.BaseBranch != run.Branch
+
+    current := make(map[string]bool)
+    for i := range issues {
+        issue := &issues[i]
+        fp := issue.Fingerprint
+        if fp == "" || issue.Status == models.StatusSuppressed {
+            continue
+        }
+
+        key := recordKey(run.Repo, run.Branch, fp)
+        rec := index[key]
+        if current[fp] {
+            // Another issue with the same fingerprint was already handled.
+            issue.Lifecycle = rec.Status
+            issue.FirstSeenCommit = rec.FirstSeenCommit
+            issue.LastSeenCommit = rec.LastSeenCommit
+            continue
+        }
+        current[fp] = true
+
+        var baseRec *models.IssueRecord
+        if hasBase {
+            baseRec = index[recordKey(run.Repo, run.BaseBranch, fp)]
+        }
+
+        var status models.LifecycleStatus
+        switch {
+        case baseRec != nil && baseRec.Status != models.LifecycleFixed:
+            status = models.LifecyclePersisting
+        case baseRec != nil:
+            status = models.LifecycleReintroduced
+        case rec != nil && rec.Status == models.LifecycleFixed:
+            status = models.LifecycleReintroduced
+        case rec != nil && hasBase:
+            // Still introduced by this branch relative to its base.
+            status = rec.Status
+            if status == models.LifecyclePersisting {
+                status = models.LifecycleNew
+            }
+        case rec != nil:
+            status = models.LifecyclePersisting
+        default:
+            status = models.LifecycleNew
+        }
+
+        if rec == nil {
+            rec = &models.IssueRecord{
+                Fingerprint:     fp,
+                Repo:            run.Repo,
+                Branch:          run.Branch,
+                FirstSeenCommit: run.Commit,
+                FirstSeenAt:     run.At,
+            }
+            if baseRec != nil {
+                rec.FirstSeenCommit = baseRec.FirstSeenCommit
+                rec.FirstSeenAt = baseRec.FirstSeenAt
+            }
+            index[key] = rec
+            db.Issues = append(db.Issues, rec)
+        }
+
+        rec.Title = issue.Title
+        rec.File = issue.File
+        rec.Line = issue.Line
+        rec.Tool = issue.Tool
+        rec.RuleID = issue.RuleID
+        rec.Severity = issue.Severity
+        rec.Status = status
+        rec.FixedCommit = ""
+        rec.LastSeenCommit = run.Commit
+        rec.LastSeenAt = run.At
+
+        issue.Lifecycle = status
+        issue.FirstSeenCommit = rec.FirstSeenCommit
+        issue.LastSeenCommit = rec.LastSeenCommit
+    }
+
+    analyzed := make(map[string]bool, len(run.Files))
+    for _, file := range run.Files {
+        analyzed[file] = true
+    }
+
+    for _, rec := range db.Issues {
+        if rec.Repo != run.Repo || rec.Status == models.LifecycleFixed || !analyzed[rec.File] || current[rec.Fingerprint] {
+            continue
+        }
+        switch rec.Branch {
+        case run.Branch:
+            rec.Status = models.LifecycleFixed
+            rec.FixedCommit = run.Commit
+        case run.BaseBranch:
+            if !hasBase || index[recordKey(run.Repo, run.Branch, rec.Fingerprint)] != nil {
+                continue
+            }
+            fixed := *rec
+            fixed.Branch = run.Branch
+            fixed.Status = models.LifecycleFixed
+            fixed.FixedCommit = run.Commit
+            index[recordKey(run.Repo, run.Branch, rec.Fingerprint)] = &fixed
+            db.Issues = append(db.Issues, &fixed)
+        }
+    }
+
+    db.Analyses = append(db.Analyses, analysisRecord{
+        ID:            run.AnalysisID,
+        Repo:          run.Repo,
+        Branch:        run.Branch,
+        Commit:        run.Commit,
+        PullRequestID: run.PullRequestID,
+        AnalyzedAt:    run.At,
+        IssueCount:    len(current),
+    })
+    s.pruneAnalyses(db, run.Repo, run.Branch)
+    pruneIssues(db)
+
+    if err := s.save(db); err != nil {
+        return nil, err
+    }
+    return summarize(db, run.Repo, run.Branch, run.BaseBranch), nil
+}
+
+// pruneAnalyses keeps the most recent maxAnalyses analyses of a branch.
+func (s *Store) pruneAnalyses(db *database, repo, branch string) {
+    count := 0
+    for _, a := range db.Analyses {
+        if a.Repo == repo && a.Branch == branch {
+            count++
+        }
+    }
+    if count <= s.maxAnalyses {
+        return
+    }
+
+    drop := count - s.maxAnalyses
+    kept := db.Analyses[:0]
+    for _, a := range db.Analyses {
+        if drop > 0 && a.Repo == repo && a.Branch == branch {
+            drop--
+            continue
+        }
+        kept = append(kept, a)
+    }
+    db.Analyses = kept
+}
+
+// pruneIssues drops the issue records whose branch has no analyses left or
+// that were last seen, or fixed, by an analysis that was pruned, and keeps
+// the most recently seen maxFixedRecords fixed records of each branch.
+func pruneIssues(db *database) {
+    commits := make(map[string]bool, len(db.Analyses))
+    for _, a := range db.Analyses {
+        commits[recordKey(a.Repo, a.Branch, a.Commit)] = true
+    }
+
+    var kept, fixed []*models.IssueRecord
+    for _, rec := range db.Issues {
+        commit := rec.LastSeenCommit
+        if rec.Status == models.LifecycleFixed {
+            commit = rec.FixedCommit
+        }
+        if !commits[recordKey(rec.Repo, rec.Branch, commit)] {
+            continue
+        }
+        if rec.Status == models.LifecycleFixed {
+            fixed = append(fixed, rec)
+        } else {
+            kept = append(kept, rec)
+        }
+    }
+
+    sort.SliceStable(fixed, func(i, j int) bool {
+        return fixed[i].LastSeenAt.After(fixed[j].LastSeenAt)
+    })
+    perBranch := make(map[string]int)
+    for _, rec := range fixed {
+        branch := recordKey(rec.Repo, rec.Branch, "")
+        if perBranch[branch] < maxFixedRecords {
+            perBranch[branch]++
+            kept = append(kept, rec)
+        }
+    }
+    db.Issues = kept
+}
+
+// Summary compares the latest recorded state of branch with baseBranch, for
+// example "3 new, 2 fixed since main". With no base branch, or a base equal
+// to branch, it reports the changes made by the branch's latest analysis.
+func (s *Store) Summary(repo, branch, baseBranch string) (*models.LifecycleSummary, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    db, err := s.load()
+    if err != nil {
+        return nil, err
+    }
+    return summarize(db, repo, branch, baseBranch), nil
+}
+
+func summarize(db *database, repo, branch, baseBranch string) *models.LifecycleSummary {
+    summary := &models.LifecycleSummary{Repo: repo, Branch: branch}
+    for i := len(db.Analyses) - 1; i >= 0; i-- {
+        if db.Analyses[i].Repo == repo && db.Analyses[i].Branch == branch {
+            summary.Commit = db.Analyses[i].Commit
+            break
+        }
+    }
+
+    if baseBranch == "" || baseBranch == branch {
+        for _, rec := range db.Issues {
+            if rec.Repo != repo || rec.Branch != branch {
+                continue
+            }
+            switch {
+            case rec.Status == models.LifecycleFixed && rec.FixedCommit == summary.Commit:
+                summary.Fixed++
+                summary.FixedIssues = append(summary.FixedIssues, *rec)
+            case rec.LastSeenCommit != summary.Commit:
+            case rec.Status == models.LifecycleNew:
+                summary.New++
+            case rec.Status == models.LifecycleReintroduced:
+                summary.Reintroduced++
+            case rec.Status == models.LifecyclePersisting:
+                summary.Persisting++
+            }
+        }
+        sortRecords(summary.FixedIssues)
+        return summary
+    }
+
+    summary.BaseBranch = baseBranch
+    base := make(map[string]*models.IssueRecord)
+    for _, rec := range db.Issues {
+        if rec.Repo == repo && rec.Branch == baseBranch {
+            base[rec.Fingerprint] = rec
+        }
+    }
+
+    for _, rec := range db.Issues {
+        if rec.Repo != repo || rec.Branch != branch {
+            continue
+        }
+        baseRec := base[rec.Fingerprint]
+        baseOpen := baseRec != nil && baseRec.Status != models.LifecycleFixed
+
+        if rec.Status == models.LifecycleFixed {
+            if baseOpen {
+                summary.Fixed++
+                summary.FixedIssues = append(summary.FixedIssues, *rec)
+            }
+            continue
+        }
+        switch {
+        case baseOpen:
+            summary.Persisting++
+        case baseRec != nil:
+            summary.Reintroduced++
+        default:
+            summary.New++
+        }
+    }
+    sortRecords(summary.FixedIssues)
+    return summary
+}
+
+func sortRecords(records []models.IssueRecord) {
+    sort.Slice(records, func(i, j int) bool {
+        if records[i].File != records[j].File {
+            return records[i].File < records[j].File
+        }
+        return records[i].Line < records[j].Line
+    })
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func openTestStore(t *testing.T, maxAnalyses int) *Store {
+    t.Helper()
+    s, err := Open(filepath.Join(t.TempDir(), "history.json"), maxAnalyses)
+    if err != nil {
+        t.Fatalf("Open failed: %v", err)
+    }
+    return s
+}
+
+func testIssue(fingerprint, file string) models.CodeIssue {
+    return models.CodeIssue{Fingerprint: fingerprint, File: file, Title: fingerprint, Tool: "ruff"}
+}
+
+// record runs an analysis of app.py on branch and returns each issue's
+// lifecycle status by fingerprint.
+func record(t *testing.T, s *Store, branch, base, commit string, issues ...models.CodeIssue) (map[string]models.LifecycleStatus, *models.LifecycleSummary) {
+    t.Helper()
+    summary, err := s.Record(Run{
+        Repo:       "example/app",
+        Branch:     branch,
+        BaseBranch: base,
+        Commit:     commit,
+        Files:      []string{"app.py"},
+        At:         time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+    }, issues)
+    if err != nil {
+        t.Fatalf("Record failed: %v", err)
+    }
+    statuses := make(map[string]models.LifecycleStatus, len(issues))
+    for _, issue := range issues {
+        statuses[issue.Fingerprint] = issue.Lifecycle
+    }
+    return statuses, summary
+}
+
+func wantStatuses(t *testing.T, got map[string]models.LifecycleStatus, want map[string]models.LifecycleStatus) {
+    t.Helper()
+    for fp, status := range want {
+        if got[fp] != status {
+            t.Errorf("%s: lifecycle = %q, want %q", fp, got[fp], status)
+        }
+    }
+}
+
+func TestRecordSameBranch(t *testing.T) {
+    s := openTestStore(t, 0)
+
+    got, summary := record(t, s, "main", "", "c1", testIssue("a", "app.py"), testIssue("b", "app.py"))
+    wantStatuses(t, got, map[string]models.LifecycleStatus{"a": models.LifecycleNew, "b": models.LifecycleNew})
+    if summary.New != 2 {
+        t.Errorf("summary.New = %d, want 2", summary.New)
+    }
+
+    // b disappears from an analyzed file and c appears.
+    got, summary = record(t, s, "main", "", "c2", testIssue("a", "app.py"), testIssue("c", "app.py"))
+    wantStatuses(t, got, map[string]models.LifecycleStatus{"a": models.LifecyclePersisting, "c": models.LifecycleNew})
+    if summary.New != 1 || summary.Persisting != 1 || summary.Fixed != 1 {
+        t.Errorf("summary = %+v, want 1 new, 1 persisting, 1 fixed", summary)
+    }
+    if len(summary.FixedIssues) != 1 || summary.FixedIssues[0].Fingerprint != "b" || summary.FixedIssues[0].FixedCommit != "c2" {
+        t.Errorf("fixed issues = %+v, want b fixed in c2", summary.FixedIssues)
+    }
+
+    got, summary = record(t, s, "main", "", "c3", testIssue("a", "app.py"), testIssue("b", "app.py"), testIssue("c", "app.py"))
+    wantStatuses(t, got, map[string]models.LifecycleStatus{
+        "a": models.LifecyclePersisting,
+        "b": models.LifecycleReintroduced,
+        "c": models.LifecyclePersisting,
+    })
+    if summary.Reintroduced != 1 || summary.Fixed != 0 {
+        t.Errorf("summary = %+v, want 1 reintroduced, none fixed", summary)
+    }
+}
+
+func TestRecordOnlyFixesAnalyzedFiles(t *testing.T) {
+    s := openTestStore(t, 0)
+
+    record(t, s, "main", "", "c1", testIssue("a", "app.py"), testIssue("b", "lib.py"))
+    _, summary := record(t, s, "main", "", "c2")
+    if summary.Fixed != 1 || summary.FixedIssues[0].Fingerprint != "a" {
+        t.Errorf("summary = %+v, want only a fixed: lib.py was not analyzed", summary)
+    }
+}
+
+func TestRecordAgainstBaseBranch(t *testing.T) {
+    s := openTestStore(t, 0)
+
+    // main has a and b open and has fixed c.
+    record(t, s, "main", "", "m1", testIssue("a", "app.py"), testIssue("b", "app.py"), testIssue("c", "app.py"))
+    record(t, s, "main", "", "m2", testIssue("a", "app.py"), testIssue("b", "app.py"))
+
+    got, summary := record(t, s, "feature", "main", "f1",
+        testIssue("a", "app.py"), testIssue("c", "app.py"), testIssue("d", "app.py"))
+    wantStatuses(t, got, map[string]models.LifecycleStatus{
+        "a": models.LifecyclePersisting,
+        "c": models.LifecycleReintroduced,
+        "d": models.LifecycleNew,
+    })
+    if summary.BaseBranch != "main" || summary.New != 1 || summary.Reintroduced != 1 || summary.Persisting != 1 || summary.Fixed != 1 {
+        t.Errorf("summary = %+v, want 1 new, 1 reintroduced, 1 persisting, 1 fixed since main", summary)
+    }
+    if len(summary.FixedIssues) != 1 || summary.FixedIssues[0].Fingerprint != "b" {
+        t.Errorf("fixed issues = %+v, want b", summary.FixedIssues)
+    }
+
+    // A second push: d is still introduced by the branch, not persisting.
+    got, _ = record(t, s, "feature", "main", "f2", testIssue("a", "app.py"), testIssue("d", "app.py"))
+    wantStatuses(t, got, map[string]models.LifecycleStatus{
+        "a": models.LifecyclePersisting,
+        "d": models.LifecycleNew,
+    })
+}
+
+func TestRecordSkipsSuppressedIssues(t *testing.T) {
+    s := openTestStore(t, 0)
+
+    suppressed := testIssue("a", "app.py")
+    suppressed.Status = models.StatusSuppressed
+    got, summary := record(t, s, "main", "", "c1", suppressed)
+    if got["a"] != "" || summary.New != 0 {
+        t.Errorf("suppressed issue recorded: lifecycle %q, summary %+v", got["a"], summary)
+    }
+}
+
+func TestRecordPrunesOldAnalyses(t *testing.T) {
+    s := openTestStore(t, 2)
+
+    record(t, s, "main", "", "c1", testIssue("a", "app.py"))
+    record(t, s, "main", "", "c2", testIssue("b", "app.py"))
+    record(t, s, "main", "", "c3", testIssue("b", "app.py"))
+    record(t, s, "main", "", "c4", testIssue("b", "app.py"))
+
+    db, err := s.load()
+    if err != nil {
+        t.Fatalf("load failed: %v", err)
+    }
+    if len(db.Analyses) != 2 {
+        t.Errorf("kept %d analyses, want 2", len(db.Analyses))
+    }
+    for _, rec := range db.Issues {
+        if rec.Fingerprint == "a" {
+            t.Errorf("kept %+v, fixed by the pruned analysis c2", rec)
+        }
+    }
+}
+
+func TestRecordAcrossStores(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "history.json")
+    // Two stores on one file stand in for two processes: they share the
+    // file lock but not the in-process mutex.
+    stores := []*Store{
+        {path: path, maxAnalyses: defaultMaxAnalyses},
+        {path: path, maxAnalyses: defaultMaxAnalyses},
+    }
+
+    const runs = 20
+    var wg sync.WaitGroup
+    for i, s := range stores {
+        wg.Add(1)
+        go func(s *Store, branch string) {
+            defer wg.Done()
+            for j := 0; j < runs; j++ {
+                if _, err := s.Record(Run{Repo: "example/app", Branch: branch, Commit: "c"}, nil); err != nil {
+                    t.Errorf("Record failed: %v", err)
+                }
+            }
+        }(s, []string{"main", "feature"}[i])
+    }
+    wg.Wait()
+
+    db, err := stores[0].load()
+    if err != nil {
+        t.Fatalf("load failed: %v", err)
+    }
+    if len(db.Analyses) != 2*runs {
+        t.Errorf("recorded %d analyses, want %d", len(db.Analyses), 2*runs)
+    }
+}
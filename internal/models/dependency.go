@@ -0,0 +1,129 @@
+package models
+
+import "sort"
+
+// Dependency scopes. Go module requirements are always runtime; Python
+// requirement files are scoped by their name.
+const (
+    ScopeRuntime = "runtime"
+    ScopeDev     = "dev"
+    ScopeTest    = "test"
+)
+
+// DependencyGraph is the dependency graph of a repository's manifests. Each
+// manifest contributes a root node for the project itself.
+type DependencyGraph struct {
+    Nodes []DependencyNode `json:"nodes"`
+    Edges []DependencyEdge `json:"edges"`
+
+    // Lookup indexes, built on first use so decoded graphs get them too.
+    nodeIndex map[string]int
+    edgeIndex map[[2]string]bool
+}
+
+// DependencyNode is a package at one version. ID is "name@version", or just
+// the name for roots and unversioned requirements. Replacement is what a Go
+// replace directive substitutes for the module.
+type DependencyNode struct {
+    ID          string `json:"id"`
+    Name        string `json:"name"`
+    Version     string `json:"version,omitempty"`
+    Ecosystem   string `json:"ecosystem"`
+    Root        bool   `json:"root,omitempty"`
+    Replacement string `json:"replacement,omitempty"`
+}
+
+// DependencyEdge says From requires To. Direct is true only for the root's
+// own requirements, and not for those its manifest only records for its
+// dependencies' sake (Go's "// indirect").
+type DependencyEdge struct {
+    From   string `json:"from"`
+    To     string `json:"to"`
+    Direct bool   `json:"direct"`
+    Scope  string `json:"scope,omitempty"`
+}
+
+// DependencyNodeID returns the node ID for name at version.
+func DependencyNodeID(name, version string) string {
+    if version == "" {
+        return name
+    }
+    return name + "@" + version
+}
+
+// AddNode adds node unless a node with its ID exists, and returns the ID.
+func (g *DependencyGraph) AddNode(node DependencyNode) string {
+    if node.ID == "" {
+        node.ID = DependencyNodeID(node.Name, node.Version)
+    }
+    if g.Node(node.ID) == nil {
+        g.nodeIndex[node.ID] = len(g.Nodes)
+        g.Nodes = append(g.Nodes, node)
+    }
+    return node.ID
+}
+
+// AddEdge adds edge unless the same edge exists.
+func (g *DependencyGraph) AddEdge(edge DependencyEdge) {
+    g.index()
+    key := [2]string{edge.From, edge.To}
+    if g.edgeIndex[key] {
+        return
+    }
+    g.edgeIndex[key] = true
+    g.Edges = append(g.Edges, edge)
+}
+
+// Node returns the node with id, or nil.
+func (g *DependencyGraph) Node(id string) *DependencyNode {
+    g.index()
+    if i, ok := g.nodeIndex[id]; ok {
+        return &g.Nodes[i]
+    }
+    return nil
+}
+
+func (g *DependencyGraph) index() {
+    if g.nodeIndex == nil || len(g.nodeIndex) != len(g.Nodes) {
+        g.nodeIndex = make(map[string]int, len(g.Nodes))
+        for i, n := range g.Nodes {
+            g.nodeIndex[n.ID] = i
+        }
+    }
+    if g.edgeIndex == nil || len(g.edgeIndex) != len(g.Edges) {
+        g.edgeIndex = make(map[[2]string]bool, len(g.Edges))
+        for _, e := range g.Edges {
+            g.edgeIndex[[2]string{e.From, e.To}] = true
+        }
+    }
+}
+
+// Roots returns the root nodes.
+func (g *DependencyGraph) Roots() []DependencyNode {
+    var roots []DependencyNode
+    for _, n := range g.Nodes {
+        if n.Root {
+            roots = append(roots, n)
+        }
+    }
+    return roots
+}
+
+// Ecosystems returns the ecosystems in the graph, sorted.
+func (g *DependencyGraph) Ecosystems() []string {
+    seen := make(map[string]bool)
+    var ecosystems []string
+    for _, n := range g.Nodes {
+        if !seen[n.Ecosystem] {
+            seen[n.Ecosystem] = true
+            ecosystems = append(ecosystems, n.Ecosystem)
+        }
+    }
+    sort.Strings(ecosystems)
+    return ecosystems
+}
+
+// Empty reports whether the graph has no edges.
+func (g *DependencyGraph) Empty() bool {
+    return g == nil || len(g.Edges) == 0
+}
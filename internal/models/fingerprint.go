@@ -0,0 +1,128 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+    whitespacePattern = regexp.MustCompile(`\s+`)
+    numberPattern     = regexp.MustCompile(`\d+`)
+)
+
+// ComputeFingerprint returns a content-based identity for an issue when its
+// source isn't available, e.g. for reports exported before fingerprints
+// existed. It hashes the tool, rule, file path and the whitespace-normalized
+// code snippet; when a tool reports no snippet, the message is used with
+// numbers stripped so embedded line/column references don't change the
+// fingerprint. Analyses use a Fingerprinter instead.
+func ComputeFingerprint(issue CodeIssue) string {
+    return fingerprintHash(issue, snippetContent(issue), 0)
+}
+
+// Fingerprinter assigns the fingerprints of one analysis. It hashes the
+// flagged source line read from the repository, so a fingerprint survives
+// the code around it moving, and counts occurrences of each identical key
+// so findings on repeated lines stay distinct.
+type Fingerprinter struct {
+    repoPath string
+    files    map[string][]string
+    seen     map[string]int
+}
+
+func NewFingerprinter(repoPath string) *Fingerprinter {
+    return &Fingerprinter{
+        repoPath: repoPath,
+        files:    make(map[string][]string),
+        seen:     make(map[string]int),
+    }
+}
+
+// Assign sets the fingerprint of every issue in the batch that has none.
+// Occurrences are counted in file and line order, so a tool's findings get
+// the same fingerprints however it orders its report. Issues whose line
+// can't be read fall back to their snippet or message.
+func (f *Fingerprinter) Assign(issues []CodeIssue) {
+    order := make([]int, 0, len(issues))
+    for i := range issues {
+        if issues[i].Fingerprint == "" {
+            order = append(order, i)
+        }
+    }
+    sort.SliceStable(order, func(a, b int) bool {
+        x, y := issues[order[a]], issues[order[b]]
+        if x.File != y.File {
+            return x.File < y.File
+        }
+        if x.Line != y.Line {
+            return x.Line < y.Line
+        }
+        return x.Column < y.Column
+    })
+
+    for _, i := range order {
+        content := f.sourceLine(issues[i].File, issues[i].Line)
+        if content == "" {
+            content = snippetContent(issues[i])
+        }
+        key := fingerprintHash(issues[i], content, 0)
+        issues[i].Fingerprint = fingerprintHash(issues[i], content, f.seen[key])
+        f.seen[key]++
+    }
+}
+
+// sourceLine returns the normalized text of line in file, or "" when the
+// file is outside the repository or can't be read.
+func (f *Fingerprinter) sourceLine(file string, line int) string {
+    if file == "" || line <= 0 || !filepath.IsLocal(filepath.FromSlash(file)) {
+        return ""
+    }
+    lines, ok := f.files[file]
+    if !ok {
+        if data, err := os.ReadFile(filepath.Join(f.repoPath, filepath.FromSlash(file))); err == nil {
+            lines = strings.Split(string(data), "\n")
+        }
+        f.files[file] = lines
+    }
+    if line > len(lines) {
+        return ""
+    }
+    return normalizeSnippet(lines[line-1])
+}
+
+func snippetContent(issue CodeIssue) string {
+    content := normalizeSnippet(issue.Code)
+    if content == "" {
+        content = numberPattern.ReplaceAllString(normalizeSnippet(issue.Description), "#")
+    }
+    return content
+}
+
+// fingerprintHash hashes the issue's identity with content. The first
+// occurrence of a key hashes without a counter.
+func fingerprintHash(issue CodeIssue, content string, occurrence int) string {
+    rule := issue.RuleID
+    if rule == "" {
+        rule = issue.Rule
+    }
+
+    h := sha256.New()
+    for _, part := range []string{strings.ToLower(issue.Tool), rule, issue.File, content} {
+        h.Write([]byte(part))
+        h.Write([]byte{0})
+    }
+    if occurrence > 0 {
+        h.Write([]byte(strconv.Itoa(occurrence)))
+    }
+    return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func normalizeSnippet(s string) string {
+    return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSource(t *testing.T, dir, name, content string) {
+    t.Helper()
+    if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+        t.Fatalf("failed to write %s: %v", name, err)
+    }
+}
+
+// assignOne fingerprints a single issue with a fresh Fingerprinter.
+func assignOne(repoPath string, issue CodeIssue) string {
+    issues := []CodeIssue{issue}
+    NewFingerprinter(repoPath).Assign(issues)
+    return issues[0].Fingerprint
+}
+
+func TestFingerprintSurvivesLineShift(t *testing.T) {
+    before, after := t.TempDir(), t.TempDir()
+    writeSource(t, before, "app.py", "import os\npassword = 'hunter2'\n")
+    writeSource(t, after, "app.py", "import os\nimport sys\n\n# settings\npassword = 'hunter2'\n")
+
+    issue := CodeIssue{Tool: "bandit", RuleID: "B105", File: "app.py", Line: 2, Description: "Possible hardcoded password on line 2"}
+    moved := issue
+    moved.Line = 5
+    moved.Description = "Possible hardcoded password on line 5"
+
+    if got, want := assignOne(after, moved), assignOne(before, issue); got != want {
+        t.Errorf("fingerprint changed when the line moved: %s, was %s", got, want)
+    }
+
+    changed := t.TempDir()
+    writeSource(t, changed, "app.py", "import os\npassword = 'correct horse'\n")
+    if assignOne(changed, issue) == assignOne(before, issue) {
+        t.Error("fingerprint unchanged when the flagged line was edited")
+    }
+}
+
+func TestFingerprintIgnoresIndentation(t *testing.T) {
+    flat, nested := t.TempDir(), t.TempDir()
+    writeSource(t, flat, "main.go", "x := compute()\n")
+    writeSource(t, nested, "main.go", "func f() {\n\tif ok {\n\t\tx  :=  compute()\n\t}\n}\n")
+
+    issue := CodeIssue{Tool: "golangci-lint", RuleID: "ineffassign", File: "main.go", Line: 1}
+    nestedIssue := issue
+    nestedIssue.Line = 3
+    if assignOne(flat, issue) != assignOne(nested, nestedIssue) {
+        t.Error("fingerprint depends on whitespace")
+    }
+}
+
+func TestFingerprintRepeatedLines(t *testing.T) {
+    dir := t.TempDir()
+    writeSource(t, dir, "main.go", "f.Close()\nother()\nf.Close()\nf.Close()\n")
+
+    newIssues := func(lines ...int) []CodeIssue {
+        var issues []CodeIssue
+        for _, line := range lines {
+            issues = append(issues, CodeIssue{Tool: "golangci-lint", RuleID: "errcheck", File: "main.go", Line: line})
+        }
+        return issues
+    }
+
+    issues := newIssues(1, 3, 4)
+    NewFingerprinter(dir).Assign(issues)
+    seen := make(map[string]bool)
+    for _, issue := range issues {
+        if seen[issue.Fingerprint] {
+            t.Fatalf("duplicate fingerprint %s on identical lines", issue.Fingerprint)
+        }
+        seen[issue.Fingerprint] = true
+    }
+
+    // The counter follows line order, not the order the tool reported in.
+    reordered := newIssues(4, 1, 3)
+    NewFingerprinter(dir).Assign(reordered)
+    if reordered[1].Fingerprint != issues[0].Fingerprint || reordered[0].Fingerprint != issues[2].Fingerprint {
+        t.Error("fingerprints depend on report order")
+    }
+
+    // The first occurrence matches a lone finding on that line.
+    if issues[0].Fingerprint != assignOne(dir, newIssues(3)[0]) {
+        t.Error("first occurrence fingerprint differs from a single finding's")
+    }
+
+    // Counters carry across batches of one analysis.
+    f := NewFingerprinter(dir)
+    first, second := newIssues(1), newIssues(3)
+    f.Assign(first)
+    f.Assign(second)
+    if first[0].Fingerprint == second[0].Fingerprint {
+        t.Error("batches of one analysis share a fingerprint")
+    }
+}
+
+func TestFingerprintFallback(t *testing.T) {
+    dir := t.TempDir()
+    issue := CodeIssue{Tool: "ruff", RuleID: "E501", File: "missing.py", Line: 3, Code: "x = 1"}
+
+    if got, want := assignOne(dir, issue), ComputeFingerprint(issue); got != want {
+        t.Errorf("unreadable file: fingerprint %s, want the snippet fallback %s", got, want)
+    }
+
+    outside := issue
+    outside.File = "../secret.py"
+    writeSource(t, filepath.Dir(dir), "secret.py", "x = 1\n")
+    if got, want := assignOne(dir, outside), ComputeFingerprint(outside); got != want {
+        t.Errorf("path outside the repository: fingerprint %s, want the fallback %s", got, want)
+    }
+
+    writeSource(t, dir, "short.py", "x = 1\n")
+    pastEnd := issue
+    pastEnd.File = "short.py"
+    pastEnd.Line = 10
+    if got, want := assignOne(dir, pastEnd), ComputeFingerprint(pastEnd); got != want {
+        t.Errorf("line past the end: fingerprint %s, want the fallback %s", got, want)
+    }
+
+    // Without a snippet the message is used, with numbers stripped.
+    noCode := CodeIssue{Tool: "ruff", RuleID: "E501", File: "missing.py", Line: 3, Description: "Line too long (120 > 88)"}
+    shifted := noCode
+    shifted.Description = "Line too long (121 > 88)"
+    if assignOne(dir, noCode) != assignOne(dir, shifted) {
+        t.Error("message fallback depends on numbers in the message")
+    }
+}
+
+func TestFingerprintKeepsExisting(t *testing.T) {
+    issues := []CodeIssue{{Tool: "semgrep", File: "app.py", Line: 1, Fingerprint: "from-sarif"}}
+    NewFingerprinter(t.TempDir()).Assign(issues)
+    if issues[0].Fingerprint != "from-sarif" {
+        t.Errorf("fingerprint = %s, want the tool's own", issues[0].Fingerprint)
+    }
+}
@@ -0,0 +1,47 @@
+package models
+
+// GateStatus is the outcome of evaluating the quality gate.
+type GateStatus string
+
+const (
+    GatePassed  GateStatus = "passed"
+    GateWarned  GateStatus = "warned"
+    GateFailed  GateStatus = "failed"
+    GateSkipped GateStatus = "skipped"
+)
+
+// QualityGateConfig configures the pass/fail policy applied to an analysis.
+type QualityGateConfig struct {
+    Enabled  bool       `yaml:"enabled"`
+    CheckRun bool       `yaml:"check_run"` // publish the verdict as a GitHub Check Run
+    Approve  bool       `yaml:"approve"`   // submit APPROVE reviews when the gate passes
+    Rules    []GateRule `yaml:"rules"`
+}
+
+// GateRule limits the number of active issues matching its filters. Empty
+// filters match everything; a rule with Max 0 forbids any matching issue.
+type GateRule struct {
+    Name     string `yaml:"name" json:"name"`
+    Severity string `yaml:"severity" json:"severity,omitempty"`
+    Type     string `yaml:"type" json:"type,omitempty"`
+    Tool     string `yaml:"tool" json:"tool,omitempty"`
+    Path     string `yaml:"path" json:"path,omitempty"`
+    NewOnly  bool   `yaml:"new_only" json:"new_only,omitempty"` // count only issues new in this change
+    Max      int    `yaml:"max" json:"max"`
+    Level    string `yaml:"level" json:"level,omitempty"` // "error" (default) fails the gate, "warning" only warns
+}
+
+// GateVerdict is the structured result of a quality gate evaluation.
+type GateVerdict struct {
+    Status  GateStatus   `json:"status"`
+    Reasons []GateReason `json:"reasons,omitempty"`
+}
+
+// GateReason explains a single violated rule.
+type GateReason struct {
+    Rule    string `json:"rule"`
+    Level   string `json:"level"`
+    Count   int    `json:"count"`
+    Max     int    `json:"max"`
+    Message string `json:"message"`
+}
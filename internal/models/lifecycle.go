@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// LifecycleStatus tracks an issue across the commits and branches of a repository.
+type LifecycleStatus string
+
+const (
+    LifecycleNew          LifecycleStatus = "new"
+    LifecyclePersisting   LifecycleStatus = "persisting"
+    LifecycleFixed        LifecycleStatus = "fixed"
+    LifecycleReintroduced LifecycleStatus = "reintroduced"
+)
+
+// IsNew reports whether the issue was introduced by the analyzed change,
+// either for the first time or after having been fixed.
+func (s LifecycleStatus) IsNew() bool {
+    return s == LifecycleNew || s == LifecycleReintroduced
+}
+
+// HistoryConfig configures the persistent issue history store.
+type HistoryConfig struct {
+    Enabled     bool   `yaml:"enabled"`
+    Path        string `yaml:"path"`
+    MaxAnalyses int    `yaml:"max_analyses"` // analyses kept per repo and branch
+}
+
+// LifecycleSummary compares a branch's issues with its base branch, or with
+// the previous analysis of the same branch when there is no base.
+type LifecycleSummary struct {
+    Repo         string        `json:"repo"`
+    Branch       string        `json:"branch"`
+    BaseBranch   string        `json:"base_branch,omitempty"`
+    Commit       string        `json:"commit"`
+    New          int           `json:"new"`
+    Persisting   int           `json:"persisting"`
+    Reintroduced int           `json:"reintroduced"`
+    Fixed        int           `json:"fixed"`
+    FixedIssues  []IssueRecord `json:"fixed_issues,omitempty"`
+}
+
+// IssueRecord is the stored history of one fingerprinted issue on a branch.
+type IssueRecord struct {
+    Fingerprint     string          `json:"fingerprint"`
+    Repo            string          `json:"repo"`
+    Branch          string          `json:"branch"`
+    Title           string          `json:"title"`
+    File            string          `json:"file"`
+    Line            int             `json:"line"`
+    Tool            string          `json:"tool"`
+    RuleID          string          `json:"rule_id,omitempty"`
+    Severity        IssueSeverity   `json:"severity"`
+    Status          LifecycleStatus `json:"status"`
+    FirstSeenCommit string          `json:"first_seen_commit"`
+    LastSeenCommit  string          `json:"last_seen_commit"`
+    FixedCommit     string          `json:"fixed_commit,omitempty"`
+    FirstSeenAt     time.Time       `json:"first_seen_at"`
+    LastSeenAt      time.Time       `json:"last_seen_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"crypto/rand"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -17,6 +19,17 @@ const (
     Hint     IssueSeverity = "HINT"
 )
 
+// ParseIssueSeverity converts a case-insensitive severity name into an
+// IssueSeverity, reporting false for values outside the enum.
+func ParseIssueSeverity(s string) (IssueSeverity, bool) {
+    switch sev := IssueSeverity(strings.ToUpper(strings.TrimSpace(s))); sev {
+    case Critical, Error, Warning, Info, Hint:
+        return sev, true
+    default:
+        return "", false
+    }
+}
+
 type IssueType string
 
 const (
@@ -31,12 +44,25 @@ const (
     AIInsight      IssueType = "AI_INSIGHT"
 )
 
+// ParseIssueType converts a case-insensitive type name into an IssueType,
+// reporting false for values outside the enum.
+func ParseIssueType(s string) (IssueType, bool) {
+    switch t := IssueType(strings.ToUpper(strings.TrimSpace(s))); t {
+    case CodeStyle, Security, Performance, Bug, Maintainability, Dependency, Test, Documentation, AIInsight:
+        return t, true
+    default:
+        return "", false
+    }
+}
+
 type CodeIssue struct {
     Title       string       `json:"title"`
     Description string       `json:"description"`
     File        string       `json:"file"`
     Line        int          `json:"line"`
     Column      int          `json:"column,omitempty"`
+    // EndLine is the last line of the flagged range, when the tool reports it.
+    EndLine     int          `json:"end_line,omitempty"`
     Severity    IssueSeverity `json:"severity"`
     Type        IssueType    `json:"type"`
     Tool        string       `json:"tool"`
@@ -51,6 +77,32 @@ type CodeIssue struct {
     Confidence string      `json:"confidence,omitempty"`
     Language   string      `json:"language,omitempty"`
     Event     WebhookEvent `json:"event,omitempty"`
+    Fingerprint string     `json:"fingerprint,omitempty"`
+    Status      IssueStatus  `json:"status,omitempty"`
+    Suppression *Suppression `json:"suppression,omitempty"`
+    OriginalSeverity IssueSeverity `json:"original_severity,omitempty"`
+    // PolicySeverity is set when a severity policy rule fixed the severity.
+    PolicySeverity   bool          `json:"policy_severity,omitempty"`
+    AIJustification  string        `json:"ai_justification,omitempty"`
+    Owners           []string      `json:"owners,omitempty"`
+    Lifecycle        LifecycleStatus `json:"lifecycle,omitempty"`
+    FirstSeenCommit  string          `json:"first_seen_commit,omitempty"`
+    LastSeenCommit   string          `json:"last_seen_commit,omitempty"`
+}
+
+// IssueStatus records whether an issue is active or has been silenced.
+type IssueStatus string
+
+const (
+    StatusSuppressed IssueStatus = "suppressed"
+)
+
+// Suppression describes the inline gollora:ignore directive that silenced an issue.
+type Suppression struct {
+    Scope         string `json:"scope"` // line or file
+    Line          int    `json:"line"`
+    Directive     string `json:"directive"`
+    Justification string `json:"justification,omitempty"`
 }
 
 type AnalysisResult struct {
@@ -62,6 +114,10 @@ type AnalysisResult struct {
     Duration     float64     `json:"duration_seconds"`
     CompletedAt  time.Time   `json:"completed_at"`
     OutputFiles  []OutputFile `json:"output_files,omitempty"`
+    QualityGate  *GateVerdict `json:"quality_gate,omitempty"`
+    Risk         *RiskScore   `json:"risk,omitempty"`
+    History      *LifecycleSummary `json:"history,omitempty"`
+    Dependencies *DependencyGraph  `json:"dependencies,omitempty"`
     mutex        sync.Mutex
 }
 
@@ -72,11 +128,13 @@ type Summary struct {
     WarningCount     int            `json:"warning_count"`
     InfoCount        int            `json:"info_count"`
     HintCount        int            `json:"hint_count"`
+    SuppressedCount  int            `json:"suppressed_count"`
     FileCount        int            `json:"file_count"`
     IssuesByType     map[string]int `json:"issues_by_type"`
     IssuesByFile     map[string]int `json:"issues_by_file"`
     IssuesByLanguage map[string]int `json:"issues_by_language"`
     IssuesByTool     map[string]int `json:"issues_by_tool"`
+    IssuesByOwner    map[string]int `json:"issues_by_owner,omitempty"`
     DependencyGraph  string         `json:"dependency_graph,omitempty"`
 }
 
@@ -107,11 +165,47 @@ func (r *AnalysisResult) AddIssue(issue CodeIssue) {
             issue.Language = DetectLanguageFromFile(issue.File)
         }
     }
+    if issue.Fingerprint == "" {
+        issue.Fingerprint = ComputeFingerprint(issue)
+    }
     
     r.mutex.Lock()
     defer r.mutex.Unlock()
 
     r.Issues = append(r.Issues, issue)
+    r.countIssue(issue)
+}
+
+// RecalculateSummary rebuilds the issue counts after issues were modified in
+// place, e.g. when suppressions or severity overrides were applied.
+func (r *AnalysisResult) RecalculateSummary() {
+    r.mutex.Lock()
+    defer r.mutex.Unlock()
+
+    summary := Summary{
+        IssuesByType:     make(map[string]int),
+        IssuesByFile:     make(map[string]int),
+        IssuesByLanguage: make(map[string]int),
+        IssuesByTool:     make(map[string]int),
+        FileCount:        r.Summary.FileCount,
+        DependencyGraph:  r.Summary.DependencyGraph,
+    }
+    r.Summary = summary
+
+    for _, issue := range r.Issues {
+        r.countIssue(issue)
+    }
+}
+
+// countIssue adds issue to the summary. Suppressed issues are tracked
+// separately so they don't inflate the severity counts.
+func (r *AnalysisResult) countIssue(issue CodeIssue) {
+    if issue.Status == StatusSuppressed {
+        r.Summary.SuppressedCount++
+        return
+    }
+
+    r.Summary.TotalIssues++
 
     switch issue.Severity {
     case Critical:
@@ -130,10 +224,45 @@ func (r *AnalysisResult) AddIssue(issue CodeIssue) {
     r.Summary.IssuesByFile[issue.File]++
 
     r.Summary.IssuesByTool[issue.Tool]++
+    for _, owner := range issue.Owners {
+        if r.Summary.IssuesByOwner == nil {
+            r.Summary.IssuesByOwner = make(map[string]int)
+        }
+        r.Summary.IssuesByOwner[owner]++
+    }
     language := DetectLanguageFromFile(issue.File)
     r.Summary.IssuesByLanguage[language]++
 }
 
+// FilterBySeverity returns a copy of the result holding only the issues the
+// threshold allows, with the summary recounted. Suppressed issues are kept
+// so reports can still audit them. A zero threshold returns r itself.
+func (r *AnalysisResult) FilterBySeverity(threshold Threshold) *AnalysisResult {
+    if threshold == (Threshold{}) {
+        return r
+    }
+
+    filtered := NewAnalysisResult(r.Event)
+    filtered.ID = r.ID
+    filtered.AnalyzedAt = r.AnalyzedAt
+    filtered.CompletedAt = r.CompletedAt
+    filtered.Duration = r.Duration
+    filtered.OutputFiles = r.OutputFiles
+    filtered.QualityGate = r.QualityGate
+    filtered.Risk = r.Risk
+    filtered.History = r.History
+    filtered.Dependencies = r.Dependencies
+    filtered.Summary.FileCount = r.Summary.FileCount
+    filtered.Summary.DependencyGraph = r.Summary.DependencyGraph
+
+    for _, issue := range r.Issues {
+        if threshold.Keeps(issue) {
+            filtered.AddIssue(issue)
+        }
+    }
+    return filtered
+}
+
 func (r *AnalysisResult) CompleteAnalysis() {
     r.CompletedAt = time.Now()
     r.Duration = r.CompletedAt.Sub(r.AnalyzedAt).Seconds()
@@ -147,11 +276,13 @@ func DetectLanguageFromFile(filePath string) string {
 
     ext := strings.ToLower(filepath.Ext(filePath))
 
+    fileName := filepath.Base(filePath)
+    if strings.HasPrefix(fileName, "Dockerfile") || strings.HasPrefix(fileName, "Containerfile") || ext == ".dockerfile" {
+        return "dockerfile"
+    }
+
     if ext == "" {
-        fileName := filepath.Base(filePath)
         switch fileName {
-        case "Dockerfile":
-            return "dockerfile"
         case "Makefile":
             return "makefile"
         case "Gemfile", "Rakefile":
@@ -169,13 +300,9 @@ func DetectLanguageFromFile(filePath string) string {
     switch ext {
     case "go":
         return "go"
-    case "js":
+    case "js", "jsx", "mjs", "cjs":
         return "javascript"
-    case "ts":
-        return "typescript"
-    case "jsx":
-        return "javascript"
-    case "tsx":
+    case "ts", "tsx", "mts", "cts":
         return "typescript"
     case "py":
         return "python"
@@ -187,7 +314,7 @@ func DetectLanguageFromFile(filePath string) string {
         return "php"
     case "c", "h":
         return "c"
-    case "cpp", "hpp", "cc":
+    case "cpp", "hpp", "cc", "hh":
         return "cpp"
     case "cs":
         return "csharp"
@@ -197,7 +324,7 @@ func DetectLanguageFromFile(filePath string) string {
         return "swift"
     case "kt", "kts":
         return "kotlin"
-    case "sh":
+    case "sh", "bash":
         return "shell"
     case "yaml", "yml":
         return "yaml"
@@ -209,6 +336,10 @@ func DetectLanguageFromFile(filePath string) string {
         return "html"
     case "css":
         return "css"
+    case "scss", "sass":
+        return "sass"
+    case "xml":
+        return "xml"
     case "sql":
         return "sql"
     case "dart":
@@ -218,15 +349,29 @@ func DetectLanguageFromFile(filePath string) string {
     }
 }
 
+// GenerateID returns a sortable, globally unique result ID.
 func GenerateID() string {
-    return time.Now().Format("20060102-150405-") + RandomString(6)
+    return time.Now().Format("20060102-150405-") + RandomString(12)
 }
 
+// RandomString returns n characters drawn from a cryptographically secure source.
 func RandomString(n int) string {
     const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
-    result := make([]byte, n)
-    for i := range result {
-        result[i] = letters[i%len(letters)]
+    // Bytes at or above the largest multiple of len(letters) are rejected so
+    // every character is equally likely.
+    const limit = 256 - 256%len(letters)
+
+    result := make([]byte, 0, n)
+    buf := make([]byte, n)
+    for len(result) < n {
+        if _, err := rand.Read(buf); err != nil {
+            panic(fmt.Sprintf("crypto/rand failed: %v", err))
+        }
+        for _, b := range buf {
+            if int(b) < limit && len(result) < n {
+                result = append(result, letters[int(b)%len(letters)])
+            }
+        }
     }
     return string(result)
-}
\ No newline at end of file
+}
@@ -0,0 +1,84 @@
+package models
+
+import "math"
+
+// RiskLevel buckets a risk score for display.
+type RiskLevel string
+
+const (
+    RiskLow      RiskLevel = "low"
+    RiskMedium   RiskLevel = "medium"
+    RiskHigh     RiskLevel = "high"
+    RiskCritical RiskLevel = "critical"
+)
+
+// RiskScore estimates how much review attention a change needs, from 0 to
+// 100, as the weighted average of its factors.
+type RiskScore struct {
+    Score   float64      `json:"score"`
+    Level   RiskLevel    `json:"level"`
+    Factors []RiskFactor `json:"factors"`
+}
+
+// RiskFactor is one input to the risk score. Score is normalized to 0-100;
+// Contribution is the share of the total score it accounts for.
+type RiskFactor struct {
+    Name         string  `json:"name"`
+    Score        float64 `json:"score"`
+    Weight       float64 `json:"weight"`
+    Contribution float64 `json:"contribution"`
+    Detail       string  `json:"detail,omitempty"`
+}
+
+// RiskConfig tunes the risk score. Weights are keyed by factor name; missing
+// factors keep their default weight and a weight of 0 disables a factor.
+type RiskConfig struct {
+    HistoryMonths int                `yaml:"history_months"`
+    Weights       map[string]float64 `yaml:"weights"`
+}
+
+// Recompute derives each factor's contribution, the total score and the
+// level from the factor scores and weights.
+func (r *RiskScore) Recompute() {
+    totalWeight := 0.0
+    for _, f := range r.Factors {
+        totalWeight += f.Weight
+    }
+
+    r.Score = 0
+    for i := range r.Factors {
+        f := &r.Factors[i]
+        f.Contribution = 0
+        if totalWeight > 0 {
+            f.Contribution = math.Round(f.Score*f.Weight/totalWeight*10) / 10
+        }
+        r.Score += f.Contribution
+    }
+    r.Score = math.Round(r.Score*10) / 10
+    r.Level = RiskLevelFor(r.Score)
+}
+
+// SetFactor replaces the factor with the same name, or appends it.
+func (r *RiskScore) SetFactor(factor RiskFactor) {
+    for i := range r.Factors {
+        if r.Factors[i].Name == factor.Name {
+            r.Factors[i] = factor
+            return
+        }
+    }
+    r.Factors = append(r.Factors, factor)
+}
+
+// RiskLevelFor maps a 0-100 score to a level.
+func RiskLevelFor(score float64) RiskLevel {
+    switch {
+    case score >= 75:
+        return RiskCritical
+    case score >= 50:
+        return RiskHigh
+    case score >= 25:
+        return RiskMedium
+    default:
+        return RiskLow
+    }
+}
@@ -0,0 +1,67 @@
+package models
+
+import "strings"
+
+// Rank orders severities from HINT (0) to CRITICAL (4). Unknown severities
+// rank below everything so they never pass a threshold by accident.
+func (s IssueSeverity) Rank() int {
+    switch s {
+    case Critical:
+        return 4
+    case Error:
+        return 3
+    case Warning:
+        return 2
+    case Info:
+        return 1
+    case Hint:
+        return 0
+    default:
+        return -1
+    }
+}
+
+// AtLeast reports whether s is at or above threshold.
+func (s IssueSeverity) AtLeast(threshold IssueSeverity) bool {
+    return s.Rank() >= threshold.Rank()
+}
+
+// Threshold is a minimum severity for a destination such as inline comments
+// or exports. The zero value lets everything through.
+type Threshold struct {
+    Min      IssueSeverity
+    Disabled bool
+}
+
+// ParseThreshold parses a threshold setting: a severity name, "none" to
+// disable the destination entirely, or "" / "all" for no filtering.
+func ParseThreshold(s string) (Threshold, bool) {
+    switch strings.ToLower(strings.TrimSpace(s)) {
+    case "", "all":
+        return Threshold{}, true
+    case "none":
+        return Threshold{Disabled: true}, true
+    }
+    severity, ok := ParseIssueSeverity(s)
+    if !ok {
+        return Threshold{}, false
+    }
+    return Threshold{Min: severity}, true
+}
+
+// Allows reports whether an issue of the given severity passes the threshold.
+func (t Threshold) Allows(severity IssueSeverity) bool {
+    if t.Disabled {
+        return false
+    }
+    if t.Min == "" {
+        return true
+    }
+    return severity.AtLeast(t.Min)
+}
+
+// Keeps reports whether an issue survives filtering at the threshold.
+// Suppressed issues are always kept so they can still be counted.
+func (t Threshold) Keeps(issue CodeIssue) bool {
+    return issue.Status == StatusSuppressed || t.Allows(issue.Severity)
+}
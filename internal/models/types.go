@@ -15,6 +15,7 @@ type WebhookEvent struct {
     ChangedFiles   []string          `json:"changed_files,omitempty"`
     Metadata       map[string]string `json:"metadata,omitempty"`
     Branch         string            `json:"branch"`
+    BaseBranch     string            `json:"base_branch,omitempty"`
 }
 
 // AnalysisRequest represents a request to analyze code
@@ -45,8 +46,13 @@ type AnalysisSettings struct {
     EnabledTools      []string `json:"enabled_tools"`
     EnableAI          bool     `json:"enable_ai"`
     ExportFormats     []string `json:"export_formats"`
-    CommentThreshold  string   `json:"comment_threshold"` // none, critical, error, warning, info
+    CommentThreshold  string   `json:"comment_threshold"` // none, critical, error, warning, info, hint
+    SummaryThreshold  string   `json:"summary_threshold"`
+    GateThreshold     string   `json:"gate_threshold"`
+    ExportThreshold   string   `json:"export_threshold"`
     IncludeDependency bool     `json:"include_dependency"`
+    // OutputDir overrides export.output_dir for the local artifact sink.
+    OutputDir         string   `json:"output_dir,omitempty"`
 }
 
 // Tool represents a code analysis tool
@@ -68,6 +74,9 @@ type Config struct {
     Server struct {
         Port int    `yaml:"port"`
         Host string `yaml:"host"`
+        // APIToken is the bearer token for /api/history, and for /reports/,
+        // which otherwise only accepts signed report links.
+        APIToken string `yaml:"api_token"`
     } `yaml:"server"`
     
     GitHub struct {
@@ -96,14 +105,102 @@ type Config struct {
         Provider string `yaml:"provider"`
         APIKey   string `yaml:"api_key"`
         Model    string `yaml:"model"`
+
+        // Severity re-scoring sends ScoringBatchSize issues per prompt and
+        // makes at most MaxScoringCalls requests per analysis.
+        ScoringBatchSize int `yaml:"scoring_batch_size"`
+        MaxScoringCalls  int `yaml:"max_scoring_calls"`
+        // OverridePolicy lets re-scoring change severities set by the
+        // severity policy, which it otherwise leaves alone.
+        OverridePolicy bool `yaml:"override_policy"`
     } `yaml:"ai"`
     
-    Export struct {
-        Formats []string `yaml:"formats"`
-    } `yaml:"export"`
+    Export ExportConfig `yaml:"export"`
+
+    Templates TemplatesConfig `yaml:"templates"`
+
+    QualityGate QualityGateConfig `yaml:"quality_gate"`
+
+    Deduplication DeduplicationConfig `yaml:"deduplication"`
+
+    Risk RiskConfig `yaml:"risk"`
+
+    History HistoryConfig `yaml:"history"`
+
+    CodeOwners struct {
+        Enabled         bool `yaml:"enabled"`
+        MentionCritical bool `yaml:"mention_critical"`
+        RequestReview   bool `yaml:"request_review"`
+    } `yaml:"codeowners"`
+
+    // Thresholds set the minimum severity each destination receives.
+    Thresholds struct {
+        Comments string `yaml:"comments"`
+        Summary  string `yaml:"summary"`
+        Gate     string `yaml:"gate"`
+        Export   string `yaml:"export"`
+    } `yaml:"thresholds"`
+}
+
+// ExportConfig selects the report formats and where the reports are stored.
+// Sink is "local" (the default) to write to OutputDir, or "s3" to upload to
+// an S3-compatible object store.
+type ExportConfig struct {
+    Formats   []string `yaml:"formats"`
+    Sink      string   `yaml:"sink"`
+    OutputDir string   `yaml:"output_dir"`
+    // BaseURL is the public URL OutputDir is served at, used to link local
+    // reports from PR comments. The server serves OutputDir at /reports/.
+    BaseURL string `yaml:"base_url"`
+    // SigningKey signs the links to local reports; /reports/ serves a
+    // report only with a valid, unexpired signature or the API token.
+    SigningKey   string   `yaml:"signing_key"`
+    LinkTTLHours int      `yaml:"link_ttl_hours"`
+    S3           S3Config `yaml:"s3"`
+}
+
+// S3Config configures the S3-compatible artifact sink. Objects are addressed
+// path-style (endpoint/bucket/key) so MinIO and similar stores work as is.
+type S3Config struct {
+    Endpoint  string `yaml:"endpoint"`
+    Region    string `yaml:"region"`
+    Bucket    string `yaml:"bucket"`
+    Prefix    string `yaml:"prefix"`
+    AccessKey string `yaml:"access_key"`
+    SecretKey string `yaml:"secret_key"`
+    // PublicURL replaces endpoint/bucket in report links, e.g. a CDN.
+    PublicURL string `yaml:"public_url"`
+}
+
+// TemplatesConfig points at text/template files replacing the built-in
+// Markdown report, PR summary, inline issue comment and comparison
+// templates. Empty paths keep the defaults.
+type TemplatesConfig struct {
+    Report  string `yaml:"report"`
+    Summary string `yaml:"summary"`
+    Issue   string `yaml:"issue"`
+    Compare string `yaml:"compare"`
 }
 
 type AnalysisToolsConfig struct {
-    Languages map[string]LanguageConfig `yaml:"languages"`
-    
-}
\ No newline at end of file
+    Languages      map[string]LanguageConfig `yaml:"languages"`
+    SeverityPolicy []SeverityRule            `yaml:"severity_policy"`
+}
+
+// SeverityRule overrides the severity and/or type of issues matching a tool,
+// rule glob and path glob. Empty match fields match everything.
+type SeverityRule struct {
+    Tool     string `yaml:"tool" json:"tool,omitempty"`
+    Rule     string `yaml:"rule" json:"rule,omitempty"`
+    Path     string `yaml:"path" json:"path,omitempty"`
+    Severity string `yaml:"severity" json:"severity,omitempty"`
+    Type     string `yaml:"type" json:"type,omitempty"`
+}
+
+// DeduplicationConfig controls how findings from different tools are merged.
+// Equivalences maps a concept name to the rules that report it, written as
+// "tool:rule", a bare rule ID for any tool, or "CWE-<n>".
+type DeduplicationConfig struct {
+    LineWindow   int                 `yaml:"line_window"`
+    Equivalences map[string][]string `yaml:"equivalences"`
+}
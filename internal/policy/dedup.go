@@ -0,0 +1,314 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// defaultLineWindow is how many lines apart two tools may report the same
+// problem and still be merged.
+const defaultLineWindow = 2
+
+// defaultEquivalences maps concepts to the rule IDs different tools use for
+// them. Entries are "tool:rule", a bare rule ID matched for any tool, or a
+// CWE ID matched against the issue's "cwe" metadata.
+var defaultEquivalences = map[string][]string{
+    "unchecked-error":        {"golangci-lint:errcheck", "G104"},
+    "ineffectual-assignment": {"golangci-lint:ineffassign", "SA4006"},
+    "hardcoded-credentials":  {"G101", "bandit:B105", "bandit:B106", "bandit:B107", "ruff:S105", "ruff:S106", "ruff:S107", "CWE-798", "CWE-259"},
+    "sql-injection":          {"G201", "G202", "bandit:B608", "ruff:S608", "CWE-89"},
+    "command-injection":      {"G204", "bandit:B602", "bandit:B605", "ruff:S602", "ruff:S605", "CWE-78"},
+    "weak-crypto":            {"G401", "G501", "bandit:B303", "bandit:B324", "ruff:S324", "CWE-327", "CWE-328"},
+    "eval-usage":             {"bandit:B307", "ruff:S307", "eslint:no-eval", "CWE-95"},
+    "unused-import":          {"flake8:F401", "ruff:F401", "pylint:W0611"},
+    "unused-variable":        {"flake8:F841", "ruff:F841", "pylint:W0612", "eslint:no-unused-vars", "eslint:@typescript-eslint/no-unused-vars", "tsc:TS6133"},
+    "undefined-name":         {"flake8:F821", "ruff:F821", "pylint:E0602", "mypy:name-defined"},
+    "bare-except":            {"flake8:E722", "ruff:E722", "pylint:W0702"},
+    "line-too-long":          {"flake8:E501", "ruff:E501", "pylint:C0301"},
+}
+
+// Deduplicator merges findings that several tools, or the AI analyzer,
+// report for the same problem at roughly the same place.
+type Deduplicator struct {
+    window   int
+    concepts map[string]string
+}
+
+// NewDeduplicator builds a deduplicator from the built-in equivalence table
+// extended by cfg. A rule listed in cfg moves to the configured concept.
+// A zero line window uses the default.
+func NewDeduplicator(cfg models.DeduplicationConfig) (*Deduplicator, error) {
+    if cfg.LineWindow < 0 {
+        return nil, fmt.Errorf("deduplication.line_window must not be negative")
+    }
+
+    d := &Deduplicator{
+        window:   cfg.LineWindow,
+        concepts: make(map[string]string),
+    }
+    if d.window == 0 {
+        d.window = defaultLineWindow
+    }
+
+    for concept, rules := range defaultEquivalences {
+        for _, rule := range rules {
+            d.concepts[strings.ToLower(rule)] = concept
+        }
+    }
+    for concept, rules := range cfg.Equivalences {
+        for i, rule := range rules {
+            rule = strings.TrimSpace(rule)
+            if rule == "" || strings.HasSuffix(rule, ":") {
+                return nil, fmt.Errorf("deduplication.equivalences.%s[%d]: empty rule", concept, i)
+            }
+            d.concepts[strings.ToLower(rule)] = concept
+        }
+    }
+
+    return d, nil
+}
+
+// dedupCluster is a group of issues believed to describe one problem.
+type dedupCluster struct {
+    members    []int
+    start, end int
+}
+
+// Merge clusters issues by file, line range and rule equivalence and
+// returns one issue per cluster along with the number of issues removed.
+// Issues from the same tool are only merged when they are exact repeats, so
+// two real findings a few lines apart stay separate. Issues without a rule,
+// such as AI findings, join a cluster covering their line when the issue
+// type matches. Suppressed issues are passed through untouched.
+func (d *Deduplicator) Merge(issues []models.CodeIssue) ([]models.CodeIssue, int) {
+    groups := make(map[string][]int)
+    var groupOrder []string
+    var unkeyed []int
+    var passthrough []int
+
+    for i, issue := range issues {
+        if issue.Status == models.StatusSuppressed {
+            passthrough = append(passthrough, i)
+            continue
+        }
+        key := d.conceptKey(issue)
+        if key == "" {
+            unkeyed = append(unkeyed, i)
+            continue
+        }
+        groupKey := issue.File + "\x00" + key
+        if _, ok := groups[groupKey]; !ok {
+            groupOrder = append(groupOrder, groupKey)
+        }
+        groups[groupKey] = append(groups[groupKey], i)
+    }
+
+    var clusters []*dedupCluster
+    clustersByFile := make(map[string][]*dedupCluster)
+
+    for _, groupKey := range groupOrder {
+        members := groups[groupKey]
+        sort.SliceStable(members, func(a, b int) bool {
+            return issues[members[a]].Line < issues[members[b]].Line
+        })
+
+        var groupClusters []*dedupCluster
+        for _, idx := range members {
+            issue := issues[idx]
+            var target *dedupCluster
+            for _, c := range groupClusters {
+                if issue.Line-c.end <= d.window && c.start-issue.Line <= d.window && d.compatible(issues, c, issue) {
+                    target = c
+                    break
+                }
+            }
+            if target == nil {
+                target = &dedupCluster{start: issue.Line, end: issue.Line}
+                groupClusters = append(groupClusters, target)
+                clusters = append(clusters, target)
+                clustersByFile[issue.File] = append(clustersByFile[issue.File], target)
+            }
+            target.add(idx, issue.Line)
+        }
+    }
+
+    exact := make(map[string]*dedupCluster)
+    for _, idx := range unkeyed {
+        issue := issues[idx]
+
+        var target *dedupCluster
+        for _, c := range clustersByFile[issue.File] {
+            if issue.Line >= c.start && issue.Line <= c.end && issues[c.members[0]].Type == issue.Type {
+                target = c
+                break
+            }
+        }
+        key := issue.File + "\x00" + strconv.Itoa(issue.Line) + "\x00" + strings.ToLower(strings.Join(strings.Fields(issue.Description), " "))
+        if target == nil {
+            target = exact[key]
+        }
+        if target == nil {
+            target = &dedupCluster{start: issue.Line, end: issue.Line}
+            exact[key] = target
+            clusters = append(clusters, target)
+        }
+        target.add(idx, issue.Line)
+    }
+
+    for _, idx := range passthrough {
+        clusters = append(clusters, &dedupCluster{members: []int{idx}})
+    }
+
+    // Keep the original order, keyed on each cluster's earliest member.
+    for _, c := range clusters {
+        sort.Ints(c.members)
+    }
+    sort.SliceStable(clusters, func(a, b int) bool {
+        return clusters[a].members[0] < clusters[b].members[0]
+    })
+
+    merged := make([]models.CodeIssue, 0, len(clusters))
+    for _, c := range clusters {
+        merged = append(merged, mergeCluster(issues, c.members))
+    }
+    return merged, len(issues) - len(merged)
+}
+
+func (c *dedupCluster) add(idx, line int) {
+    c.members = append(c.members, idx)
+    if line < c.start {
+        c.start = line
+    }
+    if line > c.end {
+        c.end = line
+    }
+}
+
+// compatible reports whether issue may join c: a tool already in the
+// cluster may only contribute exact repeats of its own findings.
+func (d *Deduplicator) compatible(issues []models.CodeIssue, c *dedupCluster, issue models.CodeIssue) bool {
+    for _, idx := range c.members {
+        member := issues[idx]
+        if !strings.EqualFold(member.Tool, issue.Tool) {
+            continue
+        }
+        if member.Line != issue.Line || member.RuleID != issue.RuleID || member.Rule != issue.Rule {
+            return false
+        }
+    }
+    return true
+}
+
+// conceptKey returns the equivalence key for an issue: a mapped concept, the
+// CWE, or the tool's own check ID. It is empty for
+// issues that carry no rule at all.
+func (d *Deduplicator) conceptKey(issue models.CodeIssue) string {
+    tool := strings.ToLower(issue.Tool)
+    cwe := strings.ToLower(issue.Metadata["cwe"])
+
+    for _, id := range []string{issue.RuleID, issue.Rule} {
+        if id == "" {
+            continue
+        }
+        id = strings.ToLower(id)
+        if concept, ok := d.concepts[tool+":"+id]; ok {
+            return "concept:" + concept
+        }
+        if concept, ok := d.concepts[id]; ok {
+            return "concept:" + concept
+        }
+    }
+
+    if cwe != "" {
+        if concept, ok := d.concepts[cwe]; ok {
+            return "concept:" + concept
+        }
+        return "cwe:" + cwe
+    }
+    // Rule is often a broad category (a PMD ruleset, a SpotBugs category),
+    // so it only identifies the check when there is no RuleID.
+    id := issue.RuleID
+    if id == "" {
+        id = issue.Rule
+    }
+    if id != "" {
+        return "rule:" + tool + ":" + strings.ToLower(id)
+    }
+    return ""
+}
+
+// mergeCluster collapses the members into one issue. The most severe
+// rule-bearing finding supplies the identity so the rule ID survives an AI
+// duplicate; the merged issue takes the highest severity of any member and
+// records every contributing tool and rule in the metadata.
+func mergeCluster(issues []models.CodeIssue, members []int) models.CodeIssue {
+    if len(members) == 1 {
+        return issues[members[0]]
+    }
+
+    primary := members[0]
+    severity := issues[primary].Severity
+    for _, idx := range members[1:] {
+        if issues[idx].Severity.Rank() > severity.Rank() {
+            severity = issues[idx].Severity
+        }
+        if betterPrimary(issues[idx], issues[primary]) {
+            primary = idx
+        }
+    }
+
+    merged := issues[primary]
+    merged.Severity = severity
+    merged.Metadata = make(map[string]string, len(issues[primary].Metadata)+3)
+    for k, v := range issues[primary].Metadata {
+        merged.Metadata[k] = v
+    }
+
+    toolSet := make(map[string]bool)
+    ruleSet := make(map[string]bool)
+    var tools, rules []string
+    for _, idx := range members {
+        issue := issues[idx]
+        if !toolSet[issue.Tool] {
+            toolSet[issue.Tool] = true
+            tools = append(tools, issue.Tool)
+        }
+        rule := issue.RuleID
+        if rule == "" {
+            rule = issue.Rule
+        }
+        if rule != "" && !ruleSet[issue.Tool+":"+rule] {
+            ruleSet[issue.Tool+":"+rule] = true
+            rules = append(rules, issue.Tool+":"+rule)
+        }
+        if merged.Fix == "" {
+            merged.Fix = issue.Fix
+        }
+        if merged.Code == "" {
+            merged.Code = issue.Code
+        }
+        if merged.Metadata["cwe"] == "" && issue.Metadata["cwe"] != "" {
+            merged.Metadata["cwe"] = issue.Metadata["cwe"]
+        }
+    }
+
+    merged.Metadata["merged_tools"] = strings.Join(tools, ",")
+    if len(rules) > 0 {
+        merged.Metadata["merged_rules"] = strings.Join(rules, ",")
+    }
+    merged.Metadata["merged_count"] = strconv.Itoa(len(members))
+    return merged
+}
+
+func betterPrimary(candidate, current models.CodeIssue) bool {
+    candidateRule := candidate.RuleID != "" || candidate.Rule != ""
+    currentRule := current.RuleID != "" || current.Rule != ""
+    if candidateRule != currentRule {
+        return candidateRule
+    }
+    return candidate.Severity.Rank() > current.Severity.Rank()
+}
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func TestDeduplicatorMerge(t *testing.T) {
+    tests := []struct {
+        name        string
+        issues      []models.CodeIssue
+        wantRemoved int
+        wantTools   []string
+    }{
+        {
+            name: "one concept from two tools",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 3, Tool: "flake8", RuleID: "F401", Severity: models.Warning},
+                {File: "app.py", Line: 3, Tool: "ruff", RuleID: "F401", Severity: models.Error},
+            },
+            wantRemoved: 1,
+            wantTools:   []string{"flake8,ruff"},
+        },
+        {
+            name: "mapped rules with different IDs",
+            issues: []models.CodeIssue{
+                {File: "main.go", Line: 10, Tool: "gosec", RuleID: "G104"},
+                {File: "main.go", Line: 11, Tool: "golangci-lint", RuleID: "errcheck"},
+            },
+            wantRemoved: 1,
+            wantTools:   []string{"gosec,golangci-lint"},
+        },
+        {
+            name: "two rules in the same PMD ruleset",
+            issues: []models.CodeIssue{
+                {File: "App.java", Line: 10, Tool: "pmd", RuleID: "UnusedLocalVariable", Rule: "Best Practices"},
+                {File: "App.java", Line: 11, Tool: "pmd", RuleID: "UnusedPrivateField", Rule: "Best Practices"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "same category name from PMD and SpotBugs",
+            issues: []models.CodeIssue{
+                {File: "App.java", Line: 40, Tool: "pmd", RuleID: "HardCodedCryptoKey", Rule: "Security"},
+                {File: "App.java", Line: 41, Tool: "spotbugs", RuleID: "PREDICTABLE_RANDOM", Rule: "SECURITY"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "rule without a check ID stays per tool",
+            issues: []models.CodeIssue{
+                {File: "main.go", Line: 5, Tool: "golangci-lint", Rule: "gocritic"},
+                {File: "main.go", Line: 5, Tool: "staticcheck", Rule: "gocritic"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "at the edge of the line window",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 10, Tool: "flake8", RuleID: "F841"},
+                {File: "app.py", Line: 12, Tool: "ruff", RuleID: "F841"},
+            },
+            wantRemoved: 1,
+            wantTools:   []string{"flake8,ruff"},
+        },
+        {
+            name: "just outside the line window",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 10, Tool: "flake8", RuleID: "F841"},
+                {File: "app.py", Line: 13, Tool: "ruff", RuleID: "F841"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "same concept in different files",
+            issues: []models.CodeIssue{
+                {File: "a.py", Line: 3, Tool: "flake8", RuleID: "F401"},
+                {File: "b.py", Line: 3, Tool: "ruff", RuleID: "F401"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "distinct findings from one tool",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 3, Tool: "ruff", RuleID: "F401"},
+                {File: "app.py", Line: 4, Tool: "ruff", RuleID: "F401"},
+            },
+            wantTools: []string{"", ""},
+        },
+        {
+            name: "AI finding on a tool finding's line",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 7, Tool: "bandit", RuleID: "B105", Type: models.Security},
+                {File: "app.py", Line: 7, Tool: "Gemini", Type: models.Security, Description: "Hardcoded password"},
+            },
+            wantRemoved: 1,
+            wantTools:   []string{"bandit,Gemini"},
+        },
+        {
+            name: "suppressed issues pass through",
+            issues: []models.CodeIssue{
+                {File: "app.py", Line: 3, Tool: "flake8", RuleID: "F401"},
+                {File: "app.py", Line: 3, Tool: "ruff", RuleID: "F401", Status: models.StatusSuppressed},
+            },
+            wantTools: []string{"", ""},
+        },
+    }
+
+    d, err := NewDeduplicator(models.DeduplicationConfig{})
+    if err != nil {
+        t.Fatalf("NewDeduplicator failed: %v", err)
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            merged, removed := d.Merge(tt.issues)
+            if removed != tt.wantRemoved {
+                t.Errorf("removed = %d, want %d", removed, tt.wantRemoved)
+            }
+            if len(merged) != len(tt.wantTools) {
+                t.Fatalf("got %d issues, want %d: %+v", len(merged), len(tt.wantTools), merged)
+            }
+            for i, want := range tt.wantTools {
+                if got := merged[i].Metadata["merged_tools"]; got != want {
+                    t.Errorf("issue %d merged_tools = %q, want %q", i, got, want)
+                }
+            }
+        })
+    }
+}
+
+func TestDeduplicatorMergeKeepsHighestSeverity(t *testing.T) {
+    d, err := NewDeduplicator(models.DeduplicationConfig{})
+    if err != nil {
+        t.Fatalf("NewDeduplicator failed: %v", err)
+    }
+    merged, _ := d.Merge([]models.CodeIssue{
+        {File: "app.py", Line: 9, Tool: "Gemini", Type: models.Security, Severity: models.Critical},
+        {File: "app.py", Line: 9, Tool: "bandit", RuleID: "B608", Type: models.Security, Severity: models.Warning},
+        {File: "app.py", Line: 10, Tool: "ruff", RuleID: "S608", Type: models.Security, Severity: models.Error},
+    })
+    if len(merged) != 1 {
+        t.Fatalf("got %d issues, want 1: %+v", len(merged), merged)
+    }
+    got := merged[0]
+    if got.Severity != models.Critical {
+        t.Errorf("severity = %s, want %s", got.Severity, models.Critical)
+    }
+    if got.RuleID == "" {
+        t.Error("merged issue lost the rule ID to the AI finding")
+    }
+    if want := "bandit:B608,ruff:S608"; got.Metadata["merged_rules"] != want {
+        t.Errorf("merged_rules = %q, want %q", got.Metadata["merged_rules"], want)
+    }
+    if got.Metadata["merged_count"] != "3" {
+        t.Errorf("merged_count = %q, want 3", got.Metadata["merged_count"])
+    }
+}
+
+func TestDeduplicatorLineWindow(t *testing.T) {
+    d, err := NewDeduplicator(models.DeduplicationConfig{LineWindow: 5})
+    if err != nil {
+        t.Fatalf("NewDeduplicator failed: %v", err)
+    }
+    _, removed := d.Merge([]models.CodeIssue{
+        {File: "app.py", Line: 10, Tool: "flake8", RuleID: "F841"},
+        {File: "app.py", Line: 15, Tool: "ruff", RuleID: "F841"},
+    })
+    if removed != 1 {
+        t.Errorf("removed = %d, want 1 with a 5-line window", removed)
+    }
+
+    if _, err := NewDeduplicator(models.DeduplicationConfig{LineWindow: -1}); err == nil {
+        t.Error("expected an error for a negative line window")
+    }
+}
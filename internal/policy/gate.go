@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// ValidateGate checks that every quality gate rule uses known severities,
+// types, levels and valid path globs.
+func ValidateGate(cfg models.QualityGateConfig) error {
+    for i, rule := range cfg.Rules {
+        if rule.Severity != "" {
+            if _, ok := models.ParseIssueSeverity(rule.Severity); !ok {
+                return fmt.Errorf("quality_gate.rules[%d]: unknown severity %q", i, rule.Severity)
+            }
+        }
+        if rule.Type != "" {
+            if _, ok := models.ParseIssueType(rule.Type); !ok {
+                return fmt.Errorf("quality_gate.rules[%d]: unknown type %q", i, rule.Type)
+            }
+        }
+        switch strings.ToLower(rule.Level) {
+        case "", "error", "warning":
+        default:
+            return fmt.Errorf("quality_gate.rules[%d]: level must be error or warning, got %q", i, rule.Level)
+        }
+        if rule.Max < 0 {
+            return fmt.Errorf("quality_gate.rules[%d]: max must not be negative", i)
+        }
+        if !validGlob(rule.Path) {
+            return fmt.Errorf("quality_gate.rules[%d]: invalid path glob %q", i, rule.Path)
+        }
+    }
+    return nil
+}
+
+// EvaluateGate applies the quality gate to an analysis result. Suppressed
+// issues and issues below threshold never count. Any violated error-level
+// rule fails the gate; if only warning-level rules are violated the gate
+// passes with a warning.
+func EvaluateGate(cfg models.QualityGateConfig, result *models.AnalysisResult, threshold models.Threshold) *models.GateVerdict {
+    if !cfg.Enabled {
+        return &models.GateVerdict{Status: models.GateSkipped}
+    }
+
+    verdict := &models.GateVerdict{Status: models.GatePassed}
+
+    for i, rule := range cfg.Rules {
+        count := 0
+        for _, issue := range result.Issues {
+            if issue.Status != models.StatusSuppressed && threshold.Allows(issue.Severity) && gateRuleMatches(rule, issue) {
+                count++
+            }
+        }
+        if count <= rule.Max {
+            continue
+        }
+
+        level := strings.ToLower(rule.Level)
+        if level == "" {
+            level = "error"
+        }
+
+        name := rule.Name
+        if name == "" {
+            name = fmt.Sprintf("rule %d", i+1)
+        }
+
+        verdict.Reasons = append(verdict.Reasons, models.GateReason{
+            Rule:    name,
+            Level:   level,
+            Count:   count,
+            Max:     rule.Max,
+            Message: fmt.Sprintf("%d %s found, at most %d allowed", count, describeGateRule(rule), rule.Max),
+        })
+
+        if level == "error" {
+            verdict.Status = models.GateFailed
+        } else if verdict.Status == models.GatePassed {
+            verdict.Status = models.GateWarned
+        }
+    }
+
+    return verdict
+}
+
+func gateRuleMatches(rule models.GateRule, issue models.CodeIssue) bool {
+    if rule.Severity != "" && !strings.EqualFold(rule.Severity, string(issue.Severity)) {
+        return false
+    }
+    if rule.Type != "" && !strings.EqualFold(rule.Type, string(issue.Type)) {
+        return false
+    }
+    if rule.Tool != "" && !strings.EqualFold(rule.Tool, issue.Tool) {
+        return false
+    }
+    if rule.NewOnly && issue.Lifecycle != "" && !issue.Lifecycle.IsNew() {
+        return false
+    }
+    return MatchGlob(rule.Path, issue.File)
+}
+
+// describeGateRule renders a rule's filters, e.g. "WARNING SECURITY issues in pkg/auth/**".
+func describeGateRule(rule models.GateRule) string {
+    var parts []string
+    if rule.Severity != "" {
+        parts = append(parts, strings.ToUpper(rule.Severity))
+    }
+    if rule.Type != "" {
+        parts = append(parts, strings.ToUpper(rule.Type))
+    }
+    if rule.NewOnly {
+        parts = append(parts, "new")
+    }
+    parts = append(parts, "issues")
+    if rule.Tool != "" {
+        parts = append(parts, "from "+rule.Tool)
+    }
+    if rule.Path != "" {
+        parts = append(parts, "in "+rule.Path)
+    }
+    return strings.Join(parts, " ")
+}
@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func gateResult(issues ...models.CodeIssue) *models.AnalysisResult {
+    result := models.NewAnalysisResult(models.WebhookEvent{})
+    for _, issue := range issues {
+        result.AddIssue(issue)
+    }
+    return result
+}
+
+func TestEvaluateGate(t *testing.T) {
+    critical := models.CodeIssue{File: "pkg/auth/token.go", Severity: models.Critical, Type: models.Security, Tool: "gosec"}
+    warning := models.CodeIssue{File: "cmd/main.go", Severity: models.Warning, Type: models.CodeStyle, Tool: "golangci-lint"}
+    suppressed := critical
+    suppressed.Status = models.StatusSuppressed
+    persisting := critical
+    persisting.Lifecycle = models.LifecyclePersisting
+    reintroduced := critical
+    reintroduced.Lifecycle = models.LifecycleReintroduced
+
+    tests := []struct {
+        name        string
+        rules       []models.GateRule
+        threshold   models.Threshold
+        issues      []models.CodeIssue
+        wantStatus  models.GateStatus
+        wantReasons []models.GateReason
+    }{
+        {
+            name:       "no violations",
+            rules:      []models.GateRule{{Name: "no criticals", Severity: "critical"}},
+            issues:     []models.CodeIssue{warning},
+            wantStatus: models.GatePassed,
+        },
+        {
+            name:       "error rule violated",
+            rules:      []models.GateRule{{Name: "no criticals", Severity: "critical"}},
+            issues:     []models.CodeIssue{critical, warning},
+            wantStatus: models.GateFailed,
+            wantReasons: []models.GateReason{
+                {Rule: "no criticals", Level: "error", Count: 1, Max: 0, Message: "1 CRITICAL issues found, at most 0 allowed"},
+            },
+        },
+        {
+            name:       "warning rule only warns",
+            rules:      []models.GateRule{{Severity: "warning", Max: 0, Level: "warning"}},
+            issues:     []models.CodeIssue{warning},
+            wantStatus: models.GateWarned,
+            wantReasons: []models.GateReason{
+                {Rule: "rule 1", Level: "warning", Count: 1, Max: 0, Message: "1 WARNING issues found, at most 0 allowed"},
+            },
+        },
+        {
+            name: "error outweighs warning",
+            rules: []models.GateRule{
+                {Name: "style", Type: "code_style", Level: "warning"},
+                {Name: "security", Type: "security", Level: "error"},
+            },
+            issues:     []models.CodeIssue{critical, warning},
+            wantStatus: models.GateFailed,
+            wantReasons: []models.GateReason{
+                {Rule: "style", Level: "warning", Count: 1, Max: 0, Message: "1 CODE_STYLE issues found, at most 0 allowed"},
+                {Rule: "security", Level: "error", Count: 1, Max: 0, Message: "1 SECURITY issues found, at most 0 allowed"},
+            },
+        },
+        {
+            name:       "within max",
+            rules:      []models.GateRule{{Name: "few warnings", Severity: "warning", Max: 2}},
+            issues:     []models.CodeIssue{warning, warning},
+            wantStatus: models.GatePassed,
+        },
+        {
+            name:       "suppressed issues don't count",
+            rules:      []models.GateRule{{Name: "no criticals", Severity: "critical"}},
+            issues:     []models.CodeIssue{suppressed},
+            wantStatus: models.GatePassed,
+        },
+        {
+            name:       "issues below threshold don't count",
+            rules:      []models.GateRule{{Name: "nothing"}},
+            threshold:  models.Threshold{Min: models.Error},
+            issues:     []models.CodeIssue{warning},
+            wantStatus: models.GatePassed,
+        },
+        {
+            name:       "tool and path filters",
+            rules:      []models.GateRule{{Name: "auth", Tool: "GOSEC", Path: "pkg/auth/**"}},
+            issues:     []models.CodeIssue{critical, warning},
+            wantStatus: models.GateFailed,
+            wantReasons: []models.GateReason{
+                {Rule: "auth", Level: "error", Count: 1, Max: 0, Message: "1 issues from GOSEC in pkg/auth/** found, at most 0 allowed"},
+            },
+        },
+        {
+            name:       "new only skips persisting issues",
+            rules:      []models.GateRule{{Name: "new criticals", Severity: "critical", NewOnly: true}},
+            issues:     []models.CodeIssue{persisting, reintroduced},
+            wantStatus: models.GateFailed,
+            wantReasons: []models.GateReason{
+                {Rule: "new criticals", Level: "error", Count: 1, Max: 0, Message: "1 CRITICAL new issues found, at most 0 allowed"},
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cfg := models.QualityGateConfig{Enabled: true, Rules: tt.rules}
+            verdict := EvaluateGate(cfg, gateResult(tt.issues...), tt.threshold)
+            if verdict.Status != tt.wantStatus {
+                t.Errorf("status = %s, want %s", verdict.Status, tt.wantStatus)
+            }
+            if !reflect.DeepEqual(verdict.Reasons, tt.wantReasons) {
+                t.Errorf("reasons:\ngot  %+v\nwant %+v", verdict.Reasons, tt.wantReasons)
+            }
+        })
+    }
+}
+
+func TestEvaluateGateDisabled(t *testing.T) {
+    cfg := models.QualityGateConfig{Rules: []models.GateRule{{Name: "anything"}}}
+    verdict := EvaluateGate(cfg, gateResult(models.CodeIssue{Severity: models.Critical}), models.Threshold{})
+    if verdict.Status != models.GateSkipped {
+        t.Errorf("status = %s, want %s", verdict.Status, models.GateSkipped)
+    }
+}
+
+func TestValidateGate(t *testing.T) {
+    tests := []struct {
+        name    string
+        rule    models.GateRule
+        wantErr bool
+    }{
+        {"valid", models.GateRule{Severity: "error", Type: "security", Level: "warning", Path: "src/**", Max: 3}, false},
+        {"unknown severity", models.GateRule{Severity: "fatal"}, true},
+        {"unknown type", models.GateRule{Type: "style"}, true},
+        {"unknown level", models.GateRule{Level: "info"}, true},
+        {"negative max", models.GateRule{Max: -1}, true},
+        {"invalid glob", models.GateRule{Path: "src/[a"}, true},
+    }
+    for _, tt := range tests {
+        err := ValidateGate(models.QualityGateConfig{Rules: []models.GateRule{tt.rule}})
+        if (err != nil) != tt.wantErr {
+            t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+        }
+    }
+}
@@ -0,0 +1,53 @@
+package policy
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether name matches pattern. Patterns use path.Match
+// syntax per segment, plus "**" to match any number of directories, so
+// "**/*_test.go" matches test files at any depth and "pkg/auth/**" matches
+// everything below pkg/auth. An empty pattern matches everything.
+func MatchGlob(pattern, name string) bool {
+    if pattern == "" {
+        return true
+    }
+    return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+    for len(pattern) > 0 {
+        if pattern[0] == "**" {
+            rest := pattern[1:]
+            if len(rest) == 0 {
+                return true
+            }
+            for i := 0; i <= len(name); i++ {
+                if matchSegments(rest, name[i:]) {
+                    return true
+                }
+            }
+            return false
+        }
+
+        if len(name) == 0 {
+            return false
+        }
+        if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+            return false
+        }
+        pattern, name = pattern[1:], name[1:]
+    }
+    return len(name) == 0
+}
+
+// validGlob reports whether every segment of pattern is a valid path.Match pattern.
+func validGlob(pattern string) bool {
+    for _, segment := range strings.Split(pattern, "/") {
+        if _, err := path.Match(segment, ""); err != nil {
+            return false
+        }
+    }
+    return true
+}
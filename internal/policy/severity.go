@@ -0,0 +1,103 @@
+// Package policy applies the team-configurable rules that decide how findings
+// are classified once the analyzers have run.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// SeverityPolicy remaps issue severities and types according to an ordered
+// list of rules from analysis_tools.yaml. For each field, the first matching
+// rule that sets it wins.
+type SeverityPolicy struct {
+    rules []models.SeverityRule
+}
+
+// NewSeverityPolicy validates rules and returns a policy for them.
+func NewSeverityPolicy(rules []models.SeverityRule) (*SeverityPolicy, error) {
+    for i, rule := range rules {
+        if rule.Severity == "" && rule.Type == "" {
+            return nil, fmt.Errorf("severity_policy[%d]: rule sets neither severity nor type", i)
+        }
+        if rule.Severity != "" {
+            if _, ok := models.ParseIssueSeverity(rule.Severity); !ok {
+                return nil, fmt.Errorf("severity_policy[%d]: unknown severity %q", i, rule.Severity)
+            }
+        }
+        if rule.Type != "" {
+            if _, ok := models.ParseIssueType(rule.Type); !ok {
+                return nil, fmt.Errorf("severity_policy[%d]: unknown type %q", i, rule.Type)
+            }
+        }
+        if !validGlob(rule.Rule) || !validGlob(rule.Path) {
+            return nil, fmt.Errorf("severity_policy[%d]: invalid glob pattern", i)
+        }
+    }
+    return &SeverityPolicy{rules: rules}, nil
+}
+
+// Apply rewrites the severity and type of matching issues in place and
+// returns how many issues changed. Issues whose severity a rule sets are
+// marked PolicySeverity, and keep the tool's severity as OriginalSeverity.
+func (p *SeverityPolicy) Apply(issues []models.CodeIssue) int {
+    if p == nil || len(p.rules) == 0 {
+        return 0
+    }
+
+    changed := 0
+    for i := range issues {
+        issue := &issues[i]
+        severitySet, typeSet := false, false
+        modified := false
+
+        for _, rule := range p.rules {
+            if severitySet && typeSet {
+                break
+            }
+            if !ruleMatches(rule, *issue) {
+                continue
+            }
+
+            if rule.Severity != "" && !severitySet {
+                severity, _ := models.ParseIssueSeverity(rule.Severity)
+                if issue.Severity != severity {
+                    if issue.OriginalSeverity == "" {
+                        issue.OriginalSeverity = issue.Severity
+                    }
+                    issue.Severity = severity
+                    modified = true
+                }
+                issue.PolicySeverity = true
+                severitySet = true
+            }
+            if rule.Type != "" && !typeSet {
+                issueType, _ := models.ParseIssueType(rule.Type)
+                if issue.Type != issueType {
+                    issue.Type = issueType
+                    modified = true
+                }
+                typeSet = true
+            }
+        }
+
+        if modified {
+            changed++
+        }
+    }
+    return changed
+}
+
+// ruleMatches checks the tool (case-insensitive), the rule glob against the
+// issue's RuleID or Rule, and the path glob against the repo-relative file.
+func ruleMatches(rule models.SeverityRule, issue models.CodeIssue) bool {
+    if rule.Tool != "" && !strings.EqualFold(rule.Tool, issue.Tool) {
+        return false
+    }
+    if rule.Rule != "" && !MatchGlob(rule.Rule, issue.RuleID) && !MatchGlob(rule.Rule, issue.Rule) {
+        return false
+    }
+    return MatchGlob(rule.Path, issue.File)
+}
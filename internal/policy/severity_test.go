@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func TestSeverityPolicyApply(t *testing.T) {
+    tests := []struct {
+        name         string
+        rules        []models.SeverityRule
+        issue        models.CodeIssue
+        wantSeverity models.IssueSeverity
+        wantType     models.IssueType
+        wantOriginal models.IssueSeverity
+        wantPolicy   bool
+        wantChanged  int
+    }{
+        {
+            name:         "tool match is case-insensitive",
+            rules:        []models.SeverityRule{{Tool: "GoSec", Severity: "critical"}},
+            issue:        models.CodeIssue{Tool: "gosec", RuleID: "G101", Severity: models.Warning},
+            wantSeverity: models.Critical,
+            wantOriginal: models.Warning,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name:         "other tool",
+            rules:        []models.SeverityRule{{Tool: "bandit", Severity: "critical"}},
+            issue:        models.CodeIssue{Tool: "gosec", RuleID: "G101", Severity: models.Warning},
+            wantSeverity: models.Warning,
+        },
+        {
+            name:         "rule glob against RuleID",
+            rules:        []models.SeverityRule{{Rule: "S1*", Severity: "hint"}},
+            issue:        models.CodeIssue{Tool: "staticcheck", RuleID: "S1005", Severity: models.Warning},
+            wantSeverity: models.Hint,
+            wantOriginal: models.Warning,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name:         "rule glob against Rule",
+            rules:        []models.SeverityRule{{Tool: "pmd", Rule: "Security", Type: "security"}},
+            issue:        models.CodeIssue{Tool: "pmd", RuleID: "HardCodedCryptoKey", Rule: "Security", Severity: models.Error, Type: models.CodeStyle},
+            wantSeverity: models.Error,
+            wantType:     models.Security,
+            wantChanged:  1,
+        },
+        {
+            name:         "rule glob mismatch",
+            rules:        []models.SeverityRule{{Rule: "G1*", Severity: "critical"}},
+            issue:        models.CodeIssue{Tool: "gosec", RuleID: "G401", Severity: models.Error},
+            wantSeverity: models.Error,
+        },
+        {
+            name:         "path glob",
+            rules:        []models.SeverityRule{{Path: "**/*_test.go", Severity: "info"}},
+            issue:        models.CodeIssue{Tool: "gosec", File: "internal/auth/token_test.go", Severity: models.Error},
+            wantSeverity: models.Info,
+            wantOriginal: models.Error,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name:         "path glob mismatch",
+            rules:        []models.SeverityRule{{Path: "**/*_test.go", Severity: "info"}},
+            issue:        models.CodeIssue{Tool: "gosec", File: "internal/auth/token.go", Severity: models.Error},
+            wantSeverity: models.Error,
+        },
+        {
+            name: "first matching rule wins",
+            rules: []models.SeverityRule{
+                {Path: "vendor/**", Severity: "hint"},
+                {Tool: "gosec", Severity: "critical"},
+                {Tool: "gosec", Severity: "info"},
+            },
+            issue:        models.CodeIssue{Tool: "gosec", File: "cmd/main.go", Severity: models.Warning},
+            wantSeverity: models.Critical,
+            wantOriginal: models.Warning,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name: "fields are decided independently",
+            rules: []models.SeverityRule{
+                {Tool: "gosec", Type: "security"},
+                {Tool: "gosec", Severity: "critical", Type: "bug"},
+            },
+            issue:        models.CodeIssue{Tool: "gosec", Severity: models.Warning, Type: models.CodeStyle},
+            wantSeverity: models.Critical,
+            wantType:     models.Security,
+            wantOriginal: models.Warning,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name:         "severity already matches",
+            rules:        []models.SeverityRule{{Tool: "gosec", Severity: "error"}},
+            issue:        models.CodeIssue{Tool: "gosec", Severity: models.Error},
+            wantSeverity: models.Error,
+            wantPolicy:   true,
+        },
+        {
+            name:         "original severity is kept from the tool",
+            rules:        []models.SeverityRule{{Tool: "gosec", Severity: "critical"}},
+            issue:        models.CodeIssue{Tool: "gosec", Severity: models.Error, OriginalSeverity: models.Warning},
+            wantSeverity: models.Critical,
+            wantOriginal: models.Warning,
+            wantPolicy:   true,
+            wantChanged:  1,
+        },
+        {
+            name:         "type only leaves severity unflagged",
+            rules:        []models.SeverityRule{{Tool: "gosec", Type: "security"}},
+            issue:        models.CodeIssue{Tool: "gosec", Severity: models.Warning, Type: models.Bug},
+            wantSeverity: models.Warning,
+            wantType:     models.Security,
+            wantChanged:  1,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            p, err := NewSeverityPolicy(tt.rules)
+            if err != nil {
+                t.Fatalf("NewSeverityPolicy failed: %v", err)
+            }
+            issues := []models.CodeIssue{tt.issue}
+            changed := p.Apply(issues)
+            got := issues[0]
+
+            wantType := tt.wantType
+            if wantType == "" {
+                wantType = tt.issue.Type
+            }
+            if changed != tt.wantChanged {
+                t.Errorf("changed = %d, want %d", changed, tt.wantChanged)
+            }
+            if got.Severity != tt.wantSeverity {
+                t.Errorf("severity = %s, want %s", got.Severity, tt.wantSeverity)
+            }
+            if got.Type != wantType {
+                t.Errorf("type = %s, want %s", got.Type, wantType)
+            }
+            if got.OriginalSeverity != tt.wantOriginal {
+                t.Errorf("original severity = %q, want %q", got.OriginalSeverity, tt.wantOriginal)
+            }
+            if got.PolicySeverity != tt.wantPolicy {
+                t.Errorf("policy severity = %v, want %v", got.PolicySeverity, tt.wantPolicy)
+            }
+        })
+    }
+}
+
+func TestSeverityPolicyNil(t *testing.T) {
+    var p *SeverityPolicy
+    issues := []models.CodeIssue{{Tool: "gosec", Severity: models.Error}}
+    if changed := p.Apply(issues); changed != 0 || issues[0].PolicySeverity {
+        t.Errorf("nil policy changed %d issues: %+v", changed, issues[0])
+    }
+}
+
+func TestNewSeverityPolicyRejectsInvalidRules(t *testing.T) {
+    tests := []struct {
+        name string
+        rule models.SeverityRule
+    }{
+        {"sets nothing", models.SeverityRule{Tool: "gosec"}},
+        {"unknown severity", models.SeverityRule{Severity: "fatal"}},
+        {"unknown type", models.SeverityRule{Type: "style"}},
+        {"invalid rule glob", models.SeverityRule{Rule: "G[1", Severity: "error"}},
+        {"invalid path glob", models.SeverityRule{Path: "src/[a", Severity: "error"}},
+    }
+    for _, tt := range tests {
+        if _, err := NewSeverityPolicy([]models.SeverityRule{tt.rule}); err == nil {
+            t.Errorf("%s: expected an error", tt.name)
+        }
+    }
+}
@@ -0,0 +1,325 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// Group is a set of issues sharing a key, as returned by groupBy.
+type Group struct {
+    Key    string
+    Issues []models.CodeIssue
+}
+
+// Count is a key with a count, as returned by counts.
+type Count struct {
+    Key   string
+    Count int
+}
+
+// OwnerCounts is one row of the findings-by-owner table.
+type OwnerCounts struct {
+    Owner                             string
+    Critical, Errors, Warnings, Total int
+}
+
+// unownedLabel groups issues in files no CODEOWNERS rule covers.
+const unownedLabel = "(unowned)"
+
+func funcMap() template.FuncMap {
+    return template.FuncMap{
+        // Filtering and sorting
+        "active":         activeIssues,
+        "suppressed":     suppressedIssues,
+        "sortBySeverity": sortBySeverity,
+        "atLeast":        atLeast,
+        "limit":          limit,
+
+        // Grouping and counting
+        "groupBy":     groupBy,
+        "counts":      counts,
+        "ownerCounts": ownerCounts,
+
+        // Text
+        "truncate":  truncate,
+        "oneline":   oneline,
+        "code":      code,
+        "cell":      cell,
+        "join":      strings.Join,
+        "lower":     strings.ToLower,
+        "upper":     strings.ToUpper,
+        "replace":   strings.ReplaceAll,
+        "hasPrefix": strings.HasPrefix,
+        "base":      path.Base,
+
+        // Presentation
+        "emoji":     severityEmoji,
+        "typeLabel": typeLabel,
+        "signed":    signed,
+        "add":       func(a, b int) int { return a + b },
+        "sub":       func(a, b int) int { return a - b },
+    }
+}
+
+func activeIssues(issues []models.CodeIssue) []models.CodeIssue {
+    var out []models.CodeIssue
+    for _, issue := range issues {
+        if issue.Status != models.StatusSuppressed {
+            out = append(out, issue)
+        }
+    }
+    return out
+}
+
+func suppressedIssues(issues []models.CodeIssue) []models.CodeIssue {
+    var out []models.CodeIssue
+    for _, issue := range issues {
+        if issue.Status == models.StatusSuppressed {
+            out = append(out, issue)
+        }
+    }
+    return out
+}
+
+// sortBySeverity returns the issues most severe first, then by file and line.
+func sortBySeverity(issues []models.CodeIssue) []models.CodeIssue {
+    out := append([]models.CodeIssue(nil), issues...)
+    sort.SliceStable(out, func(i, j int) bool {
+        if out[i].Severity.Rank() != out[j].Severity.Rank() {
+            return out[i].Severity.Rank() > out[j].Severity.Rank()
+        }
+        if out[i].File != out[j].File {
+            return out[i].File < out[j].File
+        }
+        return out[i].Line < out[j].Line
+    })
+    return out
+}
+
+// atLeast keeps the issues at or above a severity name.
+func atLeast(severity string, issues []models.CodeIssue) ([]models.CodeIssue, error) {
+    threshold, ok := models.ParseIssueSeverity(severity)
+    if !ok {
+        return nil, fmt.Errorf("unknown severity %q", severity)
+    }
+    var out []models.CodeIssue
+    for _, issue := range issues {
+        if issue.Severity.AtLeast(threshold) {
+            out = append(out, issue)
+        }
+    }
+    return out, nil
+}
+
+// limit returns the first n elements of any slice.
+func limit(n int, list interface{}) (interface{}, error) {
+    v := reflect.ValueOf(list)
+    if v.Kind() != reflect.Slice {
+        return nil, fmt.Errorf("limit: expected a slice, got %T", list)
+    }
+    if n < 0 || n >= v.Len() {
+        return list, nil
+    }
+    return v.Slice(0, n).Interface(), nil
+}
+
+// groupBy groups issues by file, type, tool, severity, rule, language or
+// owner, largest group first. Severity groups are ordered by severity.
+func groupBy(field string, issues []models.CodeIssue) ([]Group, error) {
+    index := make(map[string]int)
+    var groups []Group
+    add := func(key string, issue models.CodeIssue) {
+        i, ok := index[key]
+        if !ok {
+            i = len(groups)
+            index[key] = i
+            groups = append(groups, Group{Key: key})
+        }
+        groups[i].Issues = append(groups[i].Issues, issue)
+    }
+
+    for _, issue := range issues {
+        switch field {
+        case "file":
+            add(issue.File, issue)
+        case "type":
+            add(string(issue.Type), issue)
+        case "tool":
+            add(issue.Tool, issue)
+        case "severity":
+            add(string(issue.Severity), issue)
+        case "rule":
+            add(issue.RuleID, issue)
+        case "language":
+            add(issue.Language, issue)
+        case "owner":
+            if len(issue.Owners) == 0 {
+                add(unownedLabel, issue)
+            }
+            for _, owner := range issue.Owners {
+                add(owner, issue)
+            }
+        default:
+            return nil, fmt.Errorf("groupBy: unknown field %q", field)
+        }
+    }
+
+    sort.SliceStable(groups, func(i, j int) bool {
+        if field == "severity" {
+            return models.IssueSeverity(groups[i].Key).Rank() > models.IssueSeverity(groups[j].Key).Rank()
+        }
+        if len(groups[i].Issues) != len(groups[j].Issues) {
+            return len(groups[i].Issues) > len(groups[j].Issues)
+        }
+        return groups[i].Key < groups[j].Key
+    })
+    return groups, nil
+}
+
+// counts turns a summary map such as IssuesByType into a list, largest first.
+func counts(m map[string]int) []Count {
+    out := make([]Count, 0, len(m))
+    for key, n := range m {
+        out = append(out, Count{Key: key, Count: n})
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Count != out[j].Count {
+            return out[i].Count > out[j].Count
+        }
+        return out[i].Key < out[j].Key
+    })
+    return out
+}
+
+// ownerCounts tallies active issues per owner, most critical first.
+func ownerCounts(issues []models.CodeIssue) []OwnerCounts {
+    byOwner := make(map[string]*OwnerCounts)
+    for _, issue := range activeIssues(issues) {
+        owners := issue.Owners
+        if len(owners) == 0 {
+            owners = []string{unownedLabel}
+        }
+        for _, owner := range owners {
+            c, ok := byOwner[owner]
+            if !ok {
+                c = &OwnerCounts{Owner: owner}
+                byOwner[owner] = c
+            }
+            switch issue.Severity {
+            case models.Critical:
+                c.Critical++
+            case models.Error:
+                c.Errors++
+            case models.Warning:
+                c.Warnings++
+            }
+            c.Total++
+        }
+    }
+
+    rows := make([]OwnerCounts, 0, len(byOwner))
+    for _, c := range byOwner {
+        rows = append(rows, *c)
+    }
+    sort.Slice(rows, func(i, j int) bool {
+        if rows[i].Critical != rows[j].Critical {
+            return rows[i].Critical > rows[j].Critical
+        }
+        if rows[i].Total != rows[j].Total {
+            return rows[i].Total > rows[j].Total
+        }
+        return rows[i].Owner < rows[j].Owner
+    })
+    return rows
+}
+
+// truncate shortens s to at most n characters, ending in "...".
+func truncate(n int, s string) string {
+    r := []rune(s)
+    if len(r) <= n {
+        return s
+    }
+    if n <= 3 {
+        return string(r[:n])
+    }
+    return string(r[:n-3]) + "..."
+}
+
+// oneline collapses whitespace, including newlines, to single spaces.
+func oneline(s string) string {
+    return strings.Join(strings.Fields(s), " ")
+}
+
+// code wraps s in a Markdown code span, using a longer fence when s
+// contains backticks.
+func code(s string) string {
+    fence := "`"
+    for strings.Contains(s, fence) {
+        fence += "`"
+    }
+    if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+        return fence + " " + s + " " + fence
+    }
+    return fence + s + fence
+}
+
+// cell makes s safe for a Markdown table cell.
+func cell(s string) string {
+    return strings.ReplaceAll(oneline(s), "|", "\\|")
+}
+
+// signed formats a count change with its sign, e.g. "+3", "-1" or "0".
+func signed(n int) string {
+    if n > 0 {
+        return fmt.Sprintf("+%d", n)
+    }
+    return fmt.Sprint(n)
+}
+
+func severityEmoji(severity interface{}) string {
+    switch models.IssueSeverity(fmt.Sprint(severity)) {
+    case models.Critical:
+        return ":rotating_light:"
+    case models.Error:
+        return ":x:"
+    case models.Warning:
+        return ":warning:"
+    case models.Info:
+        return ":information_source:"
+    case models.Hint:
+        return ":bulb:"
+    default:
+        return ":mag:"
+    }
+}
+
+func typeLabel(typ interface{}) string {
+    switch t := models.IssueType(fmt.Sprint(typ)); t {
+    case models.Security:
+        return "Security"
+    case models.Performance:
+        return "Performance"
+    case models.Bug:
+        return "Bug"
+    case models.Maintainability:
+        return "Maintainability"
+    case models.Dependency:
+        return "Dependency"
+    case models.CodeStyle:
+        return "Code Style"
+    case models.Test:
+        return "Test"
+    case models.Documentation:
+        return "Documentation"
+    case models.AIInsight:
+        return "AI Insight"
+    default:
+        return string(t)
+    }
+}
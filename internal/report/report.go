@@ -0,0 +1,181 @@
+// Package report renders Markdown reports, comparisons and pull request
+// comments from text/template templates. Built-in defaults can be overridden
+// per template with a file path in config.
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/euclidstellar/gollora/internal/compare"
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// partialsFile defines the named sections ("severity_table", "history",
+// "risk", "owners", "gate", "reports") every template can use or redefine.
+const partialsFile = "templates/partials.tmpl"
+
+// Renderer holds the parsed report, summary, issue and comparison templates.
+type Renderer struct {
+    report  *template.Template
+    summary *template.Template
+    issue   *template.Template
+    compare *template.Template
+}
+
+// SummaryData is the input of the PR summary comment template.
+type SummaryData struct {
+    Result *models.AnalysisResult
+    // Threshold limits which severities the summary table shows.
+    Threshold models.Threshold
+    // Commented is how many issues met the inline comment threshold.
+    Commented int
+    // MentionOwners are @-mentioned as owners of files with CRITICAL findings.
+    MentionOwners []string
+}
+
+// SeverityRow is one row of the summary's severity table.
+type SeverityRow struct {
+    Label    string
+    Severity models.IssueSeverity
+    Count    int
+}
+
+// Rows returns the severity rows the summary threshold allows.
+func (d SummaryData) Rows() []SeverityRow {
+    s := d.Result.Summary
+    all := []SeverityRow{
+        {"Critical", models.Critical, s.CriticalCount},
+        {"Errors", models.Error, s.ErrorCount},
+        {"Warnings", models.Warning, s.WarningCount},
+        {"Infos", models.Info, s.InfoCount},
+        {"Hints", models.Hint, s.HintCount},
+    }
+
+    var rows []SeverityRow
+    for _, row := range all {
+        if d.Threshold.Allows(row.Severity) {
+            rows = append(rows, row)
+        }
+    }
+    return rows
+}
+
+// Issues returns the active issues the summary threshold allows.
+func (d SummaryData) Issues() []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, issue := range d.Result.Issues {
+        if issue.Status != models.StatusSuppressed && d.Threshold.Allows(issue.Severity) {
+            issues = append(issues, issue)
+        }
+    }
+    return issues
+}
+
+// Total sums the counts of the rows shown.
+func (d SummaryData) Total() int {
+    total := 0
+    for _, row := range d.Rows() {
+        total += row.Count
+    }
+    return total
+}
+
+// NewRenderer loads the built-in templates, replacing each one whose path
+// is set in cfg with the file's contents.
+func NewRenderer(cfg models.TemplatesConfig) (*Renderer, error) {
+    report, err := load("report", "templates/report.md.tmpl", cfg.Report)
+    if err != nil {
+        return nil, err
+    }
+    summary, err := load("summary", "templates/summary.md.tmpl", cfg.Summary)
+    if err != nil {
+        return nil, err
+    }
+    issue, err := load("issue", "templates/issue.md.tmpl", cfg.Issue)
+    if err != nil {
+        return nil, err
+    }
+    comparison, err := load("compare", "templates/compare.md.tmpl", cfg.Compare)
+    if err != nil {
+        return nil, err
+    }
+    return &Renderer{report: report, summary: summary, issue: issue, compare: comparison}, nil
+}
+
+// Default returns a renderer using only the built-in templates.
+func Default() *Renderer {
+    r, err := NewRenderer(models.TemplatesConfig{})
+    if err != nil {
+        panic(fmt.Sprintf("invalid built-in template: %v", err))
+    }
+    return r
+}
+
+func load(name, defaultFile, override string) (*template.Template, error) {
+    t := template.New(name).Funcs(funcMap())
+
+    partials, err := defaultTemplates.ReadFile(partialsFile)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := t.New("partials").Parse(string(partials)); err != nil {
+        return nil, fmt.Errorf("failed to parse built-in partials: %v", err)
+    }
+
+    body, err := defaultTemplates.ReadFile(defaultFile)
+    if err != nil {
+        return nil, err
+    }
+    source := defaultFile
+    if override != "" {
+        if body, err = os.ReadFile(override); err != nil {
+            return nil, fmt.Errorf("failed to read %s template: %v", name, err)
+        }
+        source = override
+    }
+    if _, err := t.Parse(string(body)); err != nil {
+        return nil, fmt.Errorf("failed to parse %s template %s: %v", name, source, err)
+    }
+    return t, nil
+}
+
+func execute(t *template.Template, name string, data interface{}) (string, error) {
+    var buf bytes.Buffer
+    if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+        return "", fmt.Errorf("failed to render %s template: %v", name, err)
+    }
+    return buf.String(), nil
+}
+
+// Report renders the full Markdown report.
+func (r *Renderer) Report(result *models.AnalysisResult) (string, error) {
+    return execute(r.report, "report", result)
+}
+
+// Summary renders the PR summary comment.
+func (r *Renderer) Summary(data SummaryData) (string, error) {
+    return execute(r.summary, "summary", data)
+}
+
+// Issue renders an inline review comment for one issue.
+func (r *Renderer) Issue(issue models.CodeIssue) (string, error) {
+    return execute(r.issue, "issue", issue)
+}
+
+// Comparison renders the Markdown diff of two analyses.
+func (r *Renderer) Comparison(c *compare.Comparison) (string, error) {
+    return execute(r.compare, "compare", c)
+}
+
+// Section renders one named section of the summary template set, such as
+// "gate", so it can be posted on its own.
+func (r *Renderer) Section(name string, data interface{}) (string, error) {
+    return execute(r.summary, name, data)
+}
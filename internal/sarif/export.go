@@ -0,0 +1,194 @@
+package sarif
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// FromResult converts an analysis result into a SARIF log with one run per
+// tool. Each run lists the rules its results reference, with their help
+// URIs, and every result carries the issue fingerprint under FingerprintKey
+// so dashboards can track it across uploads. Suppressed issues are kept and
+// marked as suppressed in source.
+func FromResult(result *models.AnalysisResult) *Log {
+    byTool := make(map[string][]models.CodeIssue)
+    for _, issue := range result.Issues {
+        tool := issue.Tool
+        if tool == "" {
+            tool = "gollora"
+        }
+        byTool[tool] = append(byTool[tool], issue)
+    }
+
+    tools := make([]string, 0, len(byTool))
+    for tool := range byTool {
+        tools = append(tools, tool)
+    }
+    sort.Strings(tools)
+
+    log := &Log{Schema: Schema, Version: Version, Runs: []Run{}}
+    for _, tool := range tools {
+        log.Runs = append(log.Runs, buildRun(tool, byTool[tool]))
+    }
+    return log
+}
+
+func buildRun(tool string, issues []models.CodeIssue) Run {
+    run := Run{
+        Tool:    Tool{Driver: ToolComponent{Name: tool}},
+        Results: make([]Result, 0, len(issues)),
+    }
+
+    ruleIndex := make(map[string]int)
+    for _, issue := range issues {
+        id := ruleID(issue)
+        index, ok := ruleIndex[id]
+        if !ok {
+            index = len(run.Tool.Driver.Rules)
+            ruleIndex[id] = index
+            run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, buildRule(id, issue))
+        } else if run.Tool.Driver.Rules[index].HelpURI == "" && issue.URL != "" {
+            run.Tool.Driver.Rules[index].HelpURI = issue.URL
+        }
+        run.Results = append(run.Results, buildResult(id, index, issue))
+    }
+    return run
+}
+
+// ruleID returns the issue's rule, falling back to its type for findings
+// without one (such as AI insights), since code-scanning uploads require it.
+func ruleID(issue models.CodeIssue) string {
+    if issue.RuleID != "" {
+        return issue.RuleID
+    }
+    if issue.Rule != "" {
+        return issue.Rule
+    }
+    return strings.ToLower(strings.ReplaceAll(string(issue.Type), "_", "-"))
+}
+
+// buildRule describes a rule from the first issue that references it. Issue
+// titles carry that finding's message, so none is used as the rule's
+// description; viewers show the rule ID and each result's own message.
+func buildRule(id string, issue models.CodeIssue) ReportingDescriptor {
+    rule := ReportingDescriptor{
+        ID:      id,
+        HelpURI: issue.URL,
+    }
+
+    properties := map[string]interface{}{}
+    if issue.Type != "" {
+        properties["tags"] = []string{strings.ToLower(string(issue.Type))}
+    }
+    if issue.Type == models.Security {
+        // GitHub code scanning ranks security alerts by this CVSS-style score.
+        properties["security-severity"] = securitySeverity(issue.Severity)
+    }
+    if len(properties) > 0 {
+        rule.Properties = properties
+    }
+    return rule
+}
+
+func buildResult(id string, index int, issue models.CodeIssue) Result {
+    text := issue.Description
+    if text == "" {
+        text = issue.Title
+    }
+
+    res := Result{
+        RuleID:    id,
+        RuleIndex: &index,
+        Level:     levelFor(issue.Severity),
+        Message:   Message{Text: text},
+        Properties: map[string]interface{}{
+            "severity": string(issue.Severity),
+        },
+    }
+    if issue.Type != "" {
+        res.Properties["type"] = string(issue.Type)
+    }
+    if issue.Lifecycle != "" {
+        res.Properties["lifecycle"] = string(issue.Lifecycle)
+    }
+    if len(issue.Owners) > 0 {
+        res.Properties["owners"] = issue.Owners
+    }
+    if issue.Fingerprint != "" {
+        res.PartialFingerprints = map[string]string{FingerprintKey: issue.Fingerprint}
+    }
+
+    if issue.File != "" {
+        artifact := ArtifactLocation{URI: issue.File, URIBaseID: SourceRoot}
+        loc := Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: artifact}}
+        region := issueRegion(issue)
+        if region != nil && issue.Code != "" {
+            withSnippet := *region
+            withSnippet.Snippet = &ArtifactContent{Text: issue.Code}
+            loc.PhysicalLocation.Region = &withSnippet
+        } else {
+            loc.PhysicalLocation.Region = region
+        }
+        res.Locations = []Location{loc}
+    }
+
+    // Analyzers record suggested fixes as text without the exact range they
+    // replace, so only the description is emitted; a guessed replacement
+    // would corrupt the file if a viewer applied it.
+    if issue.Fix != "" {
+        res.Fixes = []Fix{{Description: &Message{Text: issue.Fix}}}
+    }
+
+    if issue.Status == models.StatusSuppressed {
+        suppression := Suppression{Kind: "inSource"}
+        if issue.Suppression != nil {
+            suppression.Justification = issue.Suppression.Justification
+        }
+        res.Suppressions = []Suppression{suppression}
+    }
+    return res
+}
+
+func issueRegion(issue models.CodeIssue) *Region {
+    if issue.Line <= 0 {
+        return nil
+    }
+    region := &Region{StartLine: issue.Line}
+    if issue.Column > 0 {
+        region.StartColumn = issue.Column
+    }
+    if issue.EndLine > issue.Line {
+        region.EndLine = issue.EndLine
+    }
+    return region
+}
+
+// levelFor maps a severity to a SARIF level. SARIF has no level above
+// error, so CRITICAL is kept in the result properties.
+func levelFor(severity models.IssueSeverity) string {
+    switch severity {
+    case models.Critical, models.Error:
+        return "error"
+    case models.Warning:
+        return "warning"
+    case models.Info:
+        return "note"
+    default:
+        return "none"
+    }
+}
+
+func securitySeverity(severity models.IssueSeverity) string {
+    switch severity {
+    case models.Critical:
+        return "9.5"
+    case models.Error:
+        return "7.5"
+    case models.Warning:
+        return "5.0"
+    default:
+        return "2.0"
+    }
+}
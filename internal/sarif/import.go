@@ -0,0 +1,224 @@
+package sarif
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// Issues converts the log's results into CodeIssues. Each issue is
+// attributed to tool, or to its run's driver name when tool is empty. File
+// paths are the artifact URIs as reported; callers make them repo-relative.
+func (l *Log) Issues(tool string) []models.CodeIssue {
+    var issues []models.CodeIssue
+    for _, run := range l.Runs {
+        name := tool
+        if name == "" {
+            name = run.Tool.Driver.Name
+        }
+
+        rules := make(map[string]*ReportingDescriptor, len(run.Tool.Driver.Rules))
+        for i := range run.Tool.Driver.Rules {
+            rules[run.Tool.Driver.Rules[i].ID] = &run.Tool.Driver.Rules[i]
+        }
+
+        for _, res := range run.Results {
+            var rule *ReportingDescriptor
+            if res.RuleIndex != nil && *res.RuleIndex >= 0 && *res.RuleIndex < len(run.Tool.Driver.Rules) {
+                rule = &run.Tool.Driver.Rules[*res.RuleIndex]
+            } else {
+                rule = rules[res.RuleID]
+            }
+            issues = append(issues, toIssue(name, res, rule))
+        }
+    }
+    return issues
+}
+
+func toIssue(tool string, res Result, rule *ReportingDescriptor) models.CodeIssue {
+    ruleID := res.RuleID
+    if ruleID == "" && rule != nil {
+        ruleID = rule.ID
+    }
+
+    text := messageText(res.Message, rule)
+    title := shorten(text, 60)
+    if ruleID != "" {
+        title = fmt.Sprintf("%s: %s", ruleID, title)
+    }
+
+    issue := models.CodeIssue{
+        Title:       title,
+        Description: text,
+        Severity:    levelSeverity(res.Level),
+        Type:        models.CodeStyle,
+        Tool:        tool,
+        RuleID:      ruleID,
+    }
+
+    if rule != nil {
+        issue.URL = rule.HelpURI
+        if isSecurityRule(rule) {
+            issue.Type = models.Security
+            if score, ok := securityScore(rule); ok {
+                issue.Severity = scoreSeverity(score)
+            }
+        }
+    }
+
+    // Results exported by Gollora round-trip their original severity, type
+    // and fingerprint.
+    if severity, ok := res.Properties["severity"].(string); ok {
+        if parsed, ok := models.ParseIssueSeverity(severity); ok {
+            issue.Severity = parsed
+        }
+    }
+    if issueType, ok := res.Properties["type"].(string); ok {
+        if parsed, ok := models.ParseIssueType(issueType); ok {
+            issue.Type = parsed
+        }
+    }
+    issue.Fingerprint = res.PartialFingerprints[FingerprintKey]
+
+    if len(res.Locations) > 0 {
+        loc := res.Locations[0].PhysicalLocation
+        issue.File = artifactPath(loc.ArtifactLocation.URI)
+        if loc.Region != nil {
+            issue.Line = loc.Region.StartLine
+            issue.Column = loc.Region.StartColumn
+            issue.EndLine = loc.Region.EndLine
+            if loc.Region.Snippet != nil {
+                issue.Code = loc.Region.Snippet.Text
+            }
+        }
+    }
+
+    if len(res.Fixes) > 0 {
+        issue.Fix = fixText(res.Fixes[0])
+    }
+
+    for _, s := range res.Suppressions {
+        if s.Status == "" || s.Status == "accepted" {
+            issue.Status = models.StatusSuppressed
+            issue.Suppression = &models.Suppression{
+                Scope:         "sarif",
+                Line:          issue.Line,
+                Directive:     s.Kind,
+                Justification: s.Justification,
+            }
+            break
+        }
+    }
+    return issue
+}
+
+// messageText resolves a result message, substituting {0}-style arguments
+// into the rule's message string when the result only references one.
+func messageText(msg Message, rule *ReportingDescriptor) string {
+    text := msg.Text
+    if text == "" && msg.ID != "" && rule != nil {
+        text = rule.MessageStrings[msg.ID].Text
+    }
+    if text == "" {
+        text = msg.Markdown
+    }
+    if text == "" && rule != nil && rule.ShortDescription != nil {
+        text = rule.ShortDescription.Text
+    }
+    for i, arg := range msg.Arguments {
+        text = strings.ReplaceAll(text, "{"+strconv.Itoa(i)+"}", arg)
+    }
+    return text
+}
+
+func fixText(fix Fix) string {
+    var parts []string
+    for _, change := range fix.ArtifactChanges {
+        for _, r := range change.Replacements {
+            if r.InsertedContent != nil && r.InsertedContent.Text != "" {
+                parts = append(parts, r.InsertedContent.Text)
+            }
+        }
+    }
+    if len(parts) > 0 {
+        return strings.Join(parts, "\n")
+    }
+    if fix.Description != nil {
+        return fix.Description.Text
+    }
+    return ""
+}
+
+func artifactPath(uri string) string {
+    if strings.HasPrefix(uri, "file://") {
+        if u, err := url.Parse(uri); err == nil {
+            return u.Path
+        }
+    }
+    if unescaped, err := url.PathUnescape(uri); err == nil {
+        return unescaped
+    }
+    return uri
+}
+
+func isSecurityRule(rule *ReportingDescriptor) bool {
+    if _, ok := rule.Properties["security-severity"]; ok {
+        return true
+    }
+    tags, _ := rule.Properties["tags"].([]interface{})
+    for _, tag := range tags {
+        if s, ok := tag.(string); ok && strings.EqualFold(s, "security") {
+            return true
+        }
+    }
+    return false
+}
+
+func securityScore(rule *ReportingDescriptor) (float64, bool) {
+    switch v := rule.Properties["security-severity"].(type) {
+    case string:
+        score, err := strconv.ParseFloat(v, 64)
+        return score, err == nil
+    case float64:
+        return v, true
+    }
+    return 0, false
+}
+
+// scoreSeverity buckets a CVSS-style score the way GitHub code scanning does.
+func scoreSeverity(score float64) models.IssueSeverity {
+    switch {
+    case score >= 9.0:
+        return models.Critical
+    case score >= 7.0:
+        return models.Error
+    case score >= 4.0:
+        return models.Warning
+    default:
+        return models.Info
+    }
+}
+
+func levelSeverity(level string) models.IssueSeverity {
+    switch strings.ToLower(level) {
+    case "error":
+        return models.Error
+    case "note":
+        return models.Info
+    case "none":
+        return models.Hint
+    default:
+        return models.Warning
+    }
+}
+
+func shorten(text string, max int) string {
+    text = strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+    if len(text) <= max {
+        return text
+    }
+    return text[:max-3] + "..."
+}
@@ -0,0 +1,133 @@
+// Package sarif reads and writes SARIF 2.1.0 logs, the interchange format
+// used by code-scanning dashboards and many static analysis tools.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+    Version = "2.1.0"
+    Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+    // FingerprintKey names Gollora's fingerprint in partialFingerprints.
+    FingerprintKey = "gollora/v1"
+    // SourceRoot is the uriBaseId locations are relative to.
+    SourceRoot = "%SRCROOT%"
+)
+
+// Log is a SARIF log file.
+type Log struct {
+    Schema  string `json:"$schema,omitempty"`
+    Version string `json:"version"`
+    Runs    []Run  `json:"runs"`
+}
+
+// Run is the output of one tool invocation.
+type Run struct {
+    Tool    Tool     `json:"tool"`
+    Results []Result `json:"results"`
+}
+
+type Tool struct {
+    Driver ToolComponent `json:"driver"`
+}
+
+type ToolComponent struct {
+    Name           string                `json:"name"`
+    Version        string                `json:"version,omitempty"`
+    InformationURI string                `json:"informationUri,omitempty"`
+    Rules          []ReportingDescriptor `json:"rules,omitempty"`
+}
+
+// ReportingDescriptor describes a rule.
+type ReportingDescriptor struct {
+    ID               string                 `json:"id"`
+    Name             string                 `json:"name,omitempty"`
+    ShortDescription *Message               `json:"shortDescription,omitempty"`
+    FullDescription  *Message               `json:"fullDescription,omitempty"`
+    HelpURI          string                 `json:"helpUri,omitempty"`
+    Help             *Message               `json:"help,omitempty"`
+    MessageStrings   map[string]Message     `json:"messageStrings,omitempty"`
+    Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Message is a plain text message, optionally with a markdown rendering or
+// a reference to one of the rule's message strings.
+type Message struct {
+    Text      string   `json:"text,omitempty"`
+    Markdown  string   `json:"markdown,omitempty"`
+    ID        string   `json:"id,omitempty"`
+    Arguments []string `json:"arguments,omitempty"`
+}
+
+type Result struct {
+    RuleID              string                 `json:"ruleId,omitempty"`
+    RuleIndex           *int                   `json:"ruleIndex,omitempty"`
+    Level               string                 `json:"level,omitempty"`
+    Message             Message                `json:"message"`
+    Locations           []Location             `json:"locations,omitempty"`
+    PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+    Fixes               []Fix                  `json:"fixes,omitempty"`
+    Suppressions        []Suppression          `json:"suppressions,omitempty"`
+    Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type Location struct {
+    PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+    ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+    Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+    URI       string `json:"uri"`
+    URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+    StartLine   int              `json:"startLine,omitempty"`
+    StartColumn int              `json:"startColumn,omitempty"`
+    EndLine     int              `json:"endLine,omitempty"`
+    EndColumn   int              `json:"endColumn,omitempty"`
+    Snippet     *ArtifactContent `json:"snippet,omitempty"`
+}
+
+type ArtifactContent struct {
+    Text string `json:"text"`
+}
+
+// Fix is a proposed change that resolves a result.
+type Fix struct {
+    Description     *Message         `json:"description,omitempty"`
+    ArtifactChanges []ArtifactChange `json:"artifactChanges,omitempty"`
+}
+
+type ArtifactChange struct {
+    ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+    Replacements     []Replacement    `json:"replacements"`
+}
+
+type Replacement struct {
+    DeletedRegion   Region           `json:"deletedRegion"`
+    InsertedContent *ArtifactContent `json:"insertedContent,omitempty"`
+}
+
+// Suppression records that a result was silenced, and why.
+type Suppression struct {
+    Kind          string `json:"kind"` // inSource or external
+    Status        string `json:"status,omitempty"`
+    Justification string `json:"justification,omitempty"`
+}
+
+// Parse decodes a SARIF log.
+func Parse(data []byte) (*Log, error) {
+    var log Log
+    if err := json.Unmarshal(data, &log); err != nil {
+        return nil, fmt.Errorf("failed to parse SARIF log: %v", err)
+    }
+    return &log, nil
+}
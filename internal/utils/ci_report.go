@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// repositoryLevel groups findings that are not tied to a file.
+const repositoryLevel = "(repository)"
+
+type junitTestSuites struct {
+    XMLName  xml.Name         `xml:"testsuites"`
+    Name     string           `xml:"name,attr"`
+    Tests    int              `xml:"tests,attr"`
+    Failures int              `xml:"failures,attr"`
+    Skipped  int              `xml:"skipped,attr"`
+    Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+    Name     string          `xml:"name,attr"`
+    Tests    int             `xml:"tests,attr"`
+    Failures int             `xml:"failures,attr"`
+    Skipped  int             `xml:"skipped,attr"`
+    Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+    Name      string        `xml:"name,attr"`
+    ClassName string        `xml:"classname,attr"`
+    Failure   *junitFailure `xml:"failure,omitempty"`
+    Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+    Message string `xml:"message,attr"`
+    Type    string `xml:"type,attr"`
+    Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+    Message string `xml:"message,attr,omitempty"`
+}
+
+// FormatToJUnit writes the result as JUnit XML: each file with findings is
+// a testsuite and each finding a failed testcase. Suppressed findings are
+// reported as skipped so they stay visible without failing the build.
+func FormatToJUnit(result *models.AnalysisResult, outputFile string) error {
+    byFile := make(map[string][]models.CodeIssue)
+    for _, issue := range result.Issues {
+        file := issue.File
+        if file == "" {
+            file = repositoryLevel
+        }
+        byFile[file] = append(byFile[file], issue)
+    }
+
+    files := make([]string, 0, len(byFile))
+    for file := range byFile {
+        files = append(files, file)
+    }
+    sort.Strings(files)
+
+    report := junitTestSuites{Name: "gollora"}
+    for _, file := range files {
+        issues := byFile[file]
+        sort.SliceStable(issues, func(i, j int) bool {
+            return issues[i].Line < issues[j].Line
+        })
+
+        suite := junitTestSuite{Name: file}
+        for _, issue := range issues {
+            tc := junitTestCase{Name: issue.Title, ClassName: file}
+            if issue.Line > 0 {
+                tc.Name = fmt.Sprintf("line %d: %s", issue.Line, issue.Title)
+            }
+            if issue.Status == models.StatusSuppressed {
+                tc.Skipped = &junitSkipped{Message: "suppressed"}
+                if issue.Suppression != nil && issue.Suppression.Justification != "" {
+                    tc.Skipped.Message = "suppressed: " + issue.Suppression.Justification
+                }
+                suite.Skipped++
+            } else {
+                tc.Failure = &junitFailure{
+                    Message: issue.Title,
+                    Type:    string(issue.Severity),
+                    Text:    junitFailureText(issue),
+                }
+                suite.Failures++
+            }
+            suite.Tests++
+            suite.Cases = append(suite.Cases, tc)
+        }
+
+        report.Tests += suite.Tests
+        report.Failures += suite.Failures
+        report.Skipped += suite.Skipped
+        report.Suites = append(report.Suites, suite)
+    }
+
+    data, err := xml.MarshalIndent(report, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to marshal JUnit XML: %v", err)
+    }
+    data = append([]byte(xml.Header), data...)
+
+    if err := ioutil.WriteFile(outputFile, data, 0644); err != nil {
+        return fmt.Errorf("failed to write JUnit file: %v", err)
+    }
+    return nil
+}
+
+func junitFailureText(issue models.CodeIssue) string {
+    var sb strings.Builder
+    if issue.File != "" {
+        sb.WriteString(issue.File)
+        if issue.Line > 0 {
+            sb.WriteString(fmt.Sprintf(":%d", issue.Line))
+        }
+        if issue.Column > 0 {
+            sb.WriteString(fmt.Sprintf(":%d", issue.Column))
+        }
+        sb.WriteString(" ")
+    }
+    sb.WriteString(fmt.Sprintf("[%s] %s", issue.Tool, issue.Severity))
+    if issue.RuleID != "" {
+        sb.WriteString(" " + issue.RuleID)
+    }
+    if issue.Description != "" {
+        sb.WriteString("\n" + issue.Description)
+    }
+    if issue.Fix != "" {
+        sb.WriteString("\nSuggested fix: " + issue.Fix)
+    }
+    return sb.String()
+}
+
+type codeClimateIssue struct {
+    Type        string              `json:"type"`
+    CheckName   string              `json:"check_name"`
+    Description string              `json:"description"`
+    Categories  []string            `json:"categories"`
+    Location    codeClimateLocation `json:"location"`
+    Severity    string              `json:"severity"`
+    Fingerprint string              `json:"fingerprint"`
+    EngineName  string              `json:"engine_name,omitempty"`
+}
+
+type codeClimateLocation struct {
+    Path  string           `json:"path"`
+    Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+    Begin int `json:"begin"`
+    End   int `json:"end"`
+}
+
+// FormatToCodeClimate writes the active findings as a Code Climate issue
+// list, the format of GitLab's code quality report artifact. GitLab needs a
+// path and a unique fingerprint per issue, so findings without a file are
+// left out and repeated fingerprints are written once.
+func FormatToCodeClimate(result *models.AnalysisResult, outputFile string) error {
+    issues := make([]codeClimateIssue, 0, len(result.Issues))
+    seen := make(map[string]bool)
+    skipped := 0
+    for _, issue := range result.Issues {
+        if issue.Status == models.StatusSuppressed {
+            continue
+        }
+        if issue.File == "" {
+            skipped++
+            continue
+        }
+
+        line := issue.Line
+        if line < 1 {
+            line = 1
+        }
+        checkName := issue.RuleID
+        if checkName == "" {
+            checkName = strings.ToLower(string(issue.Type))
+        }
+        fingerprint := issue.Fingerprint
+        if fingerprint == "" {
+            fingerprint = models.ComputeFingerprint(issue)
+        }
+        if seen[fingerprint] {
+            continue
+        }
+        seen[fingerprint] = true
+
+        issues = append(issues, codeClimateIssue{
+            Type:        "issue",
+            CheckName:   checkName,
+            Description: issue.Title,
+            Categories:  []string{codeClimateCategory(issue.Type)},
+            Location: codeClimateLocation{
+                Path:  issue.File,
+                Lines: codeClimateLines{Begin: line, End: codeClimateEndLine(issue, line)},
+            },
+            Severity:    codeClimateSeverity(issue.Severity),
+            Fingerprint: fingerprint,
+            EngineName:  issue.Tool,
+        })
+    }
+
+    if skipped > 0 {
+        LogWithLocation(Info, "Code Climate report leaves out %d findings without a file", skipped)
+    }
+
+    data, err := json.MarshalIndent(issues, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to marshal Code Climate report: %v", err)
+    }
+
+    if err := ioutil.WriteFile(outputFile, data, 0644); err != nil {
+        return fmt.Errorf("failed to write Code Climate file: %v", err)
+    }
+    return nil
+}
+
+// codeClimateEndLine returns the end line the tool reported, so multi-line
+// findings highlight their whole range. Snippets aren't used: some carry
+// numbered context lines or marker lines rather than the flagged range.
+func codeClimateEndLine(issue models.CodeIssue, begin int) int {
+    if issue.EndLine > begin {
+        return issue.EndLine
+    }
+    return begin
+}
+
+func codeClimateSeverity(severity models.IssueSeverity) string {
+    switch severity {
+    case models.Critical:
+        return "blocker"
+    case models.Error:
+        return "critical"
+    case models.Warning:
+        return "major"
+    case models.Info:
+        return "minor"
+    default:
+        return "info"
+    }
+}
+
+// codeClimateCategory maps an issue type to one of the categories defined
+// by the Code Climate spec.
+func codeClimateCategory(issueType models.IssueType) string {
+    switch issueType {
+    case models.Security, models.Dependency:
+        return "Security"
+    case models.Bug:
+        return "Bug Risk"
+    case models.Performance:
+        return "Performance"
+    case models.Maintainability:
+        return "Complexity"
+    case models.Documentation:
+        return "Clarity"
+    default:
+        return "Style"
+    }
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+func TestCodeClimateEndLine(t *testing.T) {
+    tests := []struct {
+        name  string
+        issue models.CodeIssue
+        want  int
+    }{
+        {"tool end line", models.CodeIssue{Line: 40, EndLine: 42}, 42},
+        {"single line", models.CodeIssue{Line: 40, EndLine: 40}, 40},
+        {"no end line", models.CodeIssue{Line: 40}, 40},
+        {"bandit context lines", models.CodeIssue{Line: 40, Code: "39 import os\n40 password = 'x'\n41 \n"}, 40},
+        {"actionlint marker", models.CodeIssue{Line: 40, Code: "run: echo ${{ github.event.issue.title }}\n     ^~~~~~~~~~~~~~"}, 40},
+    }
+    for _, tt := range tests {
+        if got := codeClimateEndLine(tt.issue, tt.issue.Line); got != tt.want {
+            t.Errorf("%s: codeClimateEndLine = %d, want %d", tt.name, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/compare"
+	"github.com/euclidstellar/gollora/internal/report"
+)
+
+// FormatComparisonToMarkdown renders a comparison with the renderer's
+// compare template, or the built-in one when renderer is nil.
+func FormatComparisonToMarkdown(c *compare.Comparison, renderer *report.Renderer, outputFile string) error {
+    if renderer == nil {
+        renderer = report.Default()
+    }
+    content, err := renderer.Comparison(c)
+    if err != nil {
+        return err
+    }
+
+    if err := ioutil.WriteFile(outputFile, []byte(content), 0644); err != nil {
+        return fmt.Errorf("failed to write Markdown file: %v", err)
+    }
+    return nil
+}
+
+// FormatComparisonToHTML writes a self-contained HTML page with the deltas
+// and the new, fixed and unchanged issues of a comparison.
+func FormatComparisonToHTML(c *compare.Comparison, outputFile string) error {
+    tmpl, err := template.New("compare").Funcs(template.FuncMap{
+        "lower": strings.ToLower,
+        "signed": func(n int) string {
+            if n > 0 {
+                return fmt.Sprintf("+%d", n)
+            }
+            return fmt.Sprint(n)
+        },
+    }).Parse(htmlCompareTemplate)
+    if err != nil {
+        return fmt.Errorf("failed to parse HTML template: %v", err)
+    }
+
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, c); err != nil {
+        return fmt.Errorf("failed to render HTML comparison: %v", err)
+    }
+
+    if err := ioutil.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+        return fmt.Errorf("failed to write HTML file: %v", err)
+    }
+    return nil
+}
+
+const htmlCompareTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Code Review Comparison {{.Base.ID}}..{{.Head.ID}}</title>
+<style>
+:root { --critical: #8b0000; --error: #d32f2f; --warning: #f9a825; --info: #1976d2; --hint: #78909c; --border: #e0e0e0; }
+* { box-sizing: border-box; }
+body { font-family: -apple-system, "Segoe UI", Roboto, Arial, sans-serif; margin: 0; color: #222; background: #fafafa; }
+header { background: #263238; color: #fff; padding: 16px 24px; }
+header h1 { margin: 0 0 4px; font-size: 22px; }
+header .meta { font-size: 13px; opacity: .8; }
+main { padding: 20px 24px; max-width: 1200px; }
+section { background: #fff; border: 1px solid var(--border); border-radius: 6px; padding: 16px; margin-bottom: 20px; }
+h2 { font-size: 17px; margin: 0 0 12px; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; }
+.card { flex: 1 1 120px; border: 1px solid var(--border); border-radius: 6px; padding: 10px; text-align: center; }
+.card .n { font-size: 24px; font-weight: bold; }
+.card .l { font-size: 12px; color: #666; text-transform: uppercase; }
+.deltas { display: grid; grid-template-columns: repeat(auto-fit, minmax(300px, 1fr)); gap: 16px; }
+table.grid { border-collapse: collapse; width: 100%; font-size: 13px; }
+table.grid th, table.grid td { border: 1px solid var(--border); padding: 4px 8px; text-align: left; }
+table.grid th { background: #f2f2f2; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.up { color: var(--error); font-weight: bold; }
+.down { color: #2e7d32; font-weight: bold; }
+.badge { color: #fff; border-radius: 3px; padding: 1px 6px; font-size: 11px; font-weight: bold; background: var(--hint); }
+.badge.critical { background: var(--critical); }
+.badge.error { background: var(--error); }
+.badge.warning { background: var(--warning); }
+.badge.info { background: var(--info); }
+.loc { font-family: Consolas, Monaco, monospace; font-size: 12px; }
+.empty { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<header>
+<h1>Code Review Comparison</h1>
+<div class="meta">
+{{with .Base.Repo}}{{.}} &middot; {{end}}{{.Base.ID}}{{with .Base.ShortCommit}} ({{.}}){{end}} &rarr; {{.Head.ID}}{{with .Head.ShortCommit}} ({{.}}){{end}}
+</div>
+</header>
+<main>
+<section>
+<h2>Summary</h2>
+<div class="cards">
+<div class="card"><div class="n">{{.Base.Issues}}</div><div class="l">Base issues</div></div>
+<div class="card"><div class="n">{{.Head.Issues}}</div><div class="l">Head issues</div></div>
+<div class="card"><div class="n up">{{len .New}}</div><div class="l">New</div></div>
+<div class="card"><div class="n down">{{len .Fixed}}</div><div class="l">Fixed</div></div>
+<div class="card"><div class="n">{{len .Unchanged}}</div><div class="l">Unchanged</div></div>
+</div>
+</section>
+<section>
+<div class="deltas">
+<div><h2>By severity</h2>{{template "deltas" .BySeverity}}</div>
+<div><h2>By type</h2>{{template "deltas" .ByType}}</div>
+<div><h2>Changed files</h2>{{template "deltas" .ChangedFiles}}</div>
+</div>
+</section>
+<section>
+<h2>New issues</h2>
+{{template "issues" .New}}
+</section>
+<section>
+<h2>Fixed issues</h2>
+{{template "issues" .Fixed}}
+</section>
+<section>
+<details>
+<summary><strong>Unchanged issues ({{len .Unchanged}})</strong></summary>
+{{template "issues" .Unchanged}}
+</details>
+</section>
+</main>
+</body>
+</html>
+{{define "deltas"}}{{if .}}<table class="grid"><tr><th></th><th>Base</th><th>Head</th><th>Change</th></tr>
+{{range .}}<tr><td>{{.Key}}</td><td class="num">{{.Base}}</td><td class="num">{{.Head}}</td><td class="num{{if gt .Change 0}} up{{else if lt .Change 0}} down{{end}}">{{signed .Change}}</td></tr>
+{{end}}</table>{{else}}<p class="empty">None</p>{{end}}{{end}}
+{{define "issues"}}{{if .}}<table class="grid"><tr><th>Severity</th><th>Location</th><th>Tool</th><th>Issue</th></tr>
+{{range .}}<tr><td><span class="badge {{lower (print .Severity)}}">{{.Severity}}</span></td><td class="loc">{{.File}}{{if .Line}}:{{.Line}}{{end}}</td><td>{{.Tool}}{{with .RuleID}} &middot; {{.}}{{end}}</td><td>{{.Title}}</td></tr>
+{{end}}</table>{{else}}<p class="empty">None</p>{{end}}{{end}}
+`
@@ -10,8 +10,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/euclidstellar/gollora/internal/models"
 )
 
 type GitCloneOptions struct {
@@ -168,6 +171,20 @@ func GetLatestCommitHash(repoPath string) (string, error) {
     return strings.TrimSpace(string(output)), nil
 }
 
+// GetCurrentBranch returns the checked-out branch name, or "HEAD" when the
+// repository is in detached HEAD state.
+func GetCurrentBranch(repoPath string) (string, error) {
+    cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+    cmd.Dir = repoPath
+
+    output, err := cmd.Output()
+    if err != nil {
+        return "", fmt.Errorf("failed to get current branch: %v", err)
+    }
+
+    return strings.TrimSpace(string(output)), nil
+}
+
 func GetFileContent(repoPath, filePath, commit string) ([]byte, error) {
     cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commit, filePath))
     cmd.Dir = repoPath
@@ -180,55 +197,83 @@ func GetFileContent(repoPath, filePath, commit string) ([]byte, error) {
     return output, nil
 }
 
-func DetectFileLanguage(filePath string) string {
-    ext := strings.ToLower(filepath.Ext(filePath))
-    
-    switch ext {
-    case ".go":
-        return "go"
-    case ".js":
-        return "javascript"
-    case ".ts":
-        return "typescript"
-    case ".jsx", ".tsx":
-        return "react"
-    case ".java":
-        return "java"
-    case ".py":
-        return "python"
-    case ".rb":
-        return "ruby"
-    case ".php":
-        return "php"
-    case ".c", ".h":
-        return "c"
-    case ".cpp", ".hpp", ".cc", ".hh":
-        return "cpp"
-    case ".cs":
-        return "csharp"
-    case ".swift":
-        return "swift"
-    case ".kt", ".kts":
-        return "kotlin"
-    case ".rs":
-        return "rust"
-    case ".yaml", ".yml":
-        return "yaml"
-    case ".json":
-        return "json"
-    case ".md":
-        return "markdown"
-    case ".html", ".htm":
-        return "html"
-    case ".css":
-        return "css"
-    case ".scss", ".sass":
-        return "sass"
-    case ".xml":
-        return "xml"
-    case ".sh", ".bash":
-        return "shell"
-    default:
-        return "unknown"
+// DiffStat is the number of lines added and removed in one file.
+type DiffStat struct {
+    Added   int
+    Removed int
+}
+
+// GetDiffStats returns per-file line counts between two commits. Binary
+// files are reported with zero counts.
+func GetDiffStats(repoPath, baseCommit, headCommit string) (map[string]DiffStat, error) {
+    cmd := exec.Command("git", "diff", "--numstat", baseCommit, headCommit)
+    cmd.Dir = repoPath
+
+    output, err := cmd.Output()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get diff stats: %v", err)
+    }
+
+    stats := make(map[string]DiffStat)
+    for _, line := range strings.Split(string(output), "\n") {
+        fields := strings.SplitN(line, "\t", 3)
+        if len(fields) != 3 {
+            continue
+        }
+        added, _ := strconv.Atoi(fields[0])
+        removed, _ := strconv.Atoi(fields[1])
+        stats[fields[2]] = DiffStat{Added: added, Removed: removed}
     }
-}
\ No newline at end of file
+    return stats, nil
+}
+
+// FileHistory summarizes the recent commits touching one file.
+type FileHistory struct {
+    Commits int
+    Authors map[string]int // commits per author email
+}
+
+// GetFileHistory returns the commit history since the given time for each of
+// files, reachable from HEAD and excluding merges.
+func GetFileHistory(repoPath string, since time.Time, files []string) (map[string]FileHistory, error) {
+    history := make(map[string]FileHistory)
+    if len(files) == 0 {
+        return history, nil
+    }
+
+    args := []string{"log", "--no-merges", "--since=" + since.Format(time.RFC3339), "--format=@%ae", "--name-only", "HEAD", "--"}
+    args = append(args, files...)
+    cmd := exec.Command("git", args...)
+    cmd.Dir = repoPath
+
+    output, err := cmd.Output()
+    if err != nil {
+        return nil, fmt.Errorf("failed to read file history: %v", err)
+    }
+
+    author := ""
+    for _, line := range strings.Split(string(output), "\n") {
+        line = strings.TrimSpace(line)
+        if line == "" {
+            continue
+        }
+        if strings.HasPrefix(line, "@") {
+            author = strings.ToLower(line[1:])
+            continue
+        }
+        h := history[line]
+        if h.Authors == nil {
+            h.Authors = make(map[string]int)
+        }
+        h.Commits++
+        h.Authors[author]++
+        history[line] = h
+    }
+    return history, nil
+}
+
+// DetectFileLanguage returns the language key used to select analyzers.
+// It defers to models.DetectLanguageFromFile so every caller agrees.
+func DetectFileLanguage(filePath string) string {
+    return models.DetectLanguageFromFile(filePath)
+}
@@ -0,0 +1,524 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/depgraph"
+	"github.com/euclidstellar/gollora/internal/models"
+)
+
+// snippetContext is how many lines are shown around a finding.
+const snippetContext = 3
+
+var htmlSeverities = []models.IssueSeverity{models.Critical, models.Error, models.Warning, models.Info, models.Hint}
+
+type htmlReport struct {
+    Result      *models.AnalysisResult
+    Generated   string
+    Active      int
+    Severities  []htmlBar
+    Types       []htmlBar
+    Tools       []htmlBar
+    Tree        []*htmlTreeNode
+    Issues      []htmlIssue
+    Suppressed  []htmlIssue
+    TypeNames   []string
+    ToolNames   []string
+    Graph       []htmlGraphGroup
+    GraphSource string
+}
+
+// htmlBar is one row of a bar chart; Percent is relative to the largest row.
+type htmlBar struct {
+    Label   string
+    Class   string
+    Count   int
+    Percent int
+}
+
+type htmlTreeNode struct {
+    Name     string
+    Path     string
+    IsFile   bool
+    Count    int
+    Critical int
+    Children []*htmlTreeNode
+}
+
+type htmlIssue struct {
+    models.CodeIssue
+    Index       int
+    SeverityCSS string
+    Snippet     []htmlLine
+}
+
+type htmlLine struct {
+    Number    int
+    Text      string
+    Highlight bool
+}
+
+type htmlGraphGroup struct {
+    Name  string
+    Edges []htmlGraphEdge
+}
+
+type htmlGraphEdge struct {
+    From string
+    To   string
+    Kind string
+}
+
+// FormatToHTML writes a self-contained HTML report with inline CSS and JS:
+// severity charts, a file tree with issue counts, filterable findings with
+// source snippets read from repoPath, and the dependency graph. It needs no
+// network access to view.
+func FormatToHTML(result *models.AnalysisResult, repoPath, outputFile string) error {
+    report := buildHTMLReport(result, repoPath)
+
+    tmpl, err := template.New("report").Parse(htmlReportTemplate)
+    if err != nil {
+        return fmt.Errorf("failed to parse HTML template: %v", err)
+    }
+
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, report); err != nil {
+        return fmt.Errorf("failed to render HTML report: %v", err)
+    }
+
+    if err := ioutil.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+        return fmt.Errorf("failed to write HTML file: %v", err)
+    }
+    return nil
+}
+
+func buildHTMLReport(result *models.AnalysisResult, repoPath string) *htmlReport {
+    report := &htmlReport{
+        Result:    result,
+        Generated: result.CompletedAt.Format("2006-01-02 15:04:05 MST"),
+    }
+    if result.CompletedAt.IsZero() {
+        report.Generated = result.AnalyzedAt.Format("2006-01-02 15:04:05 MST")
+    }
+
+    severityCounts := make(map[models.IssueSeverity]int)
+    typeCounts := make(map[string]int)
+    toolCounts := make(map[string]int)
+    lines := newSourceCache(repoPath)
+
+    var active []models.CodeIssue
+    for _, issue := range result.Issues {
+        if issue.Status == models.StatusSuppressed {
+            report.Suppressed = append(report.Suppressed, htmlIssue{CodeIssue: issue})
+            continue
+        }
+        active = append(active, issue)
+        severityCounts[issue.Severity]++
+        typeCounts[string(issue.Type)]++
+        toolCounts[issue.Tool]++
+    }
+    report.Active = len(active)
+
+    sort.SliceStable(active, func(i, j int) bool {
+        if active[i].Severity.Rank() != active[j].Severity.Rank() {
+            return active[i].Severity.Rank() > active[j].Severity.Rank()
+        }
+        if active[i].File != active[j].File {
+            return active[i].File < active[j].File
+        }
+        return active[i].Line < active[j].Line
+    })
+    for i, issue := range active {
+        report.Issues = append(report.Issues, htmlIssue{
+            CodeIssue:   issue,
+            Index:       i + 1,
+            SeverityCSS: strings.ToLower(string(issue.Severity)),
+            Snippet:     lines.snippet(issue),
+        })
+    }
+
+    for _, severity := range htmlSeverities {
+        report.Severities = append(report.Severities, htmlBar{
+            Label: string(severity),
+            Class: strings.ToLower(string(severity)),
+            Count: severityCounts[severity],
+        })
+    }
+    report.Types = countBars(typeCounts)
+    report.Tools = countBars(toolCounts)
+    scaleBars(report.Severities)
+    for name := range typeCounts {
+        report.TypeNames = append(report.TypeNames, name)
+    }
+    for name := range toolCounts {
+        report.ToolNames = append(report.ToolNames, name)
+    }
+    sort.Strings(report.TypeNames)
+    sort.Strings(report.ToolNames)
+
+    report.Tree = buildFileTree(active)
+    report.GraphSource = result.Summary.DependencyGraph
+    report.Graph = dependencyGroups(result.Dependencies)
+    return report
+}
+
+// countBars turns counts into bars, largest first.
+func countBars(counts map[string]int) []htmlBar {
+    bars := make([]htmlBar, 0, len(counts))
+    for label, count := range counts {
+        bars = append(bars, htmlBar{Label: label, Count: count})
+    }
+    sort.Slice(bars, func(i, j int) bool {
+        if bars[i].Count != bars[j].Count {
+            return bars[i].Count > bars[j].Count
+        }
+        return bars[i].Label < bars[j].Label
+    })
+    scaleBars(bars)
+    return bars
+}
+
+func scaleBars(bars []htmlBar) {
+    max := 0
+    for _, b := range bars {
+        if b.Count > max {
+            max = b.Count
+        }
+    }
+    for i := range bars {
+        if max > 0 {
+            bars[i].Percent = bars[i].Count * 100 / max
+        }
+    }
+}
+
+// buildFileTree groups issue counts by directory, directories first.
+func buildFileTree(issues []models.CodeIssue) []*htmlTreeNode {
+    root := &htmlTreeNode{}
+    for _, issue := range issues {
+        if issue.File == "" {
+            continue
+        }
+        node := root
+        node.Count++
+        parts := strings.Split(filepath.ToSlash(issue.File), "/")
+        for i, part := range parts {
+            var child *htmlTreeNode
+            for _, c := range node.Children {
+                if c.Name == part {
+                    child = c
+                    break
+                }
+            }
+            if child == nil {
+                child = &htmlTreeNode{
+                    Name:   part,
+                    Path:   strings.Join(parts[:i+1], "/"),
+                    IsFile: i == len(parts)-1,
+                }
+                node.Children = append(node.Children, child)
+            }
+            child.Count++
+            if issue.Severity == models.Critical {
+                child.Critical++
+            }
+            node = child
+        }
+    }
+    sortTree(root.Children)
+    return root.Children
+}
+
+func sortTree(nodes []*htmlTreeNode) {
+    sort.Slice(nodes, func(i, j int) bool {
+        if nodes[i].IsFile != nodes[j].IsFile {
+            return !nodes[i].IsFile
+        }
+        return nodes[i].Name < nodes[j].Name
+    })
+    for _, n := range nodes {
+        sortTree(n.Children)
+    }
+}
+
+// dependencyGroups lists the dependency graph's edges by ecosystem so it
+// can be shown without Mermaid.js.
+func dependencyGroups(graph *models.DependencyGraph) []htmlGraphGroup {
+    if graph.Empty() {
+        return nil
+    }
+    var groups []htmlGraphGroup
+    for _, ecosystem := range graph.Ecosystems() {
+        group := htmlGraphGroup{Name: depgraph.EcosystemTitle(ecosystem)}
+        for _, e := range graph.Edges {
+            from, to := graph.Node(e.From), graph.Node(e.To)
+            if from == nil || to == nil || from.Ecosystem != ecosystem {
+                continue
+            }
+            kind := "indirect"
+            if e.Direct {
+                kind = "direct"
+            }
+            if e.Scope != "" && e.Scope != models.ScopeRuntime {
+                kind += ", " + e.Scope
+            }
+            group.Edges = append(group.Edges, htmlGraphEdge{From: depgraph.Label(*from), To: depgraph.Label(*to), Kind: kind})
+        }
+        if len(group.Edges) > 0 {
+            groups = append(groups, group)
+        }
+    }
+    return groups
+}
+
+// sourceCache reads each source file once for snippet extraction.
+type sourceCache struct {
+    repoPath string
+    files    map[string][]string
+}
+
+func newSourceCache(repoPath string) *sourceCache {
+    return &sourceCache{repoPath: repoPath, files: make(map[string][]string)}
+}
+
+func (c *sourceCache) lines(file string) []string {
+    if lines, ok := c.files[file]; ok {
+        return lines
+    }
+    var lines []string
+    if c.repoPath != "" {
+        if f, err := os.Open(filepath.Join(c.repoPath, file)); err == nil {
+            scanner := bufio.NewScanner(f)
+            scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+            for scanner.Scan() {
+                lines = append(lines, scanner.Text())
+            }
+            f.Close()
+        }
+    }
+    c.files[file] = lines
+    return lines
+}
+
+// snippet returns the lines around an issue, or the issue's own code when
+// the file can't be read.
+func (c *sourceCache) snippet(issue models.CodeIssue) []htmlLine {
+    if issue.File != "" && issue.Line > 0 {
+        lines := c.lines(issue.File)
+        if issue.Line <= len(lines) {
+            start := issue.Line - snippetContext
+            if start < 1 {
+                start = 1
+            }
+            end := issue.Line + snippetContext
+            if end > len(lines) {
+                end = len(lines)
+            }
+            snippet := make([]htmlLine, 0, end-start+1)
+            for n := start; n <= end; n++ {
+                snippet = append(snippet, htmlLine{Number: n, Text: lines[n-1], Highlight: n == issue.Line})
+            }
+            return snippet
+        }
+    }
+
+    if issue.Code == "" {
+        return nil
+    }
+    var snippet []htmlLine
+    for i, text := range strings.Split(strings.TrimRight(issue.Code, "\n"), "\n") {
+        line := htmlLine{Text: text}
+        if issue.Line > 0 {
+            line.Number = issue.Line + i
+            line.Highlight = i == 0
+        }
+        snippet = append(snippet, line)
+    }
+    return snippet
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Code Review Report {{.Result.ID}}</title>
+<style>
+:root { --critical: #8b0000; --error: #d32f2f; --warning: #f9a825; --info: #1976d2; --hint: #78909c; --border: #e0e0e0; }
+* { box-sizing: border-box; }
+body { font-family: -apple-system, "Segoe UI", Roboto, Arial, sans-serif; margin: 0; color: #222; background: #fafafa; }
+header { background: #263238; color: #fff; padding: 16px 24px; }
+header h1 { margin: 0 0 4px; font-size: 22px; }
+header .meta { font-size: 13px; opacity: .8; }
+main { display: grid; grid-template-columns: 300px 1fr; gap: 20px; padding: 20px 24px; }
+aside, section { background: #fff; border: 1px solid var(--border); border-radius: 6px; padding: 16px; }
+section { margin-bottom: 20px; }
+h2 { font-size: 17px; margin: 0 0 12px; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; }
+.card { flex: 1 1 120px; border: 1px solid var(--border); border-radius: 6px; padding: 10px; text-align: center; }
+.card .n { font-size: 24px; font-weight: bold; }
+.card .l { font-size: 12px; color: #666; text-transform: uppercase; }
+.charts { display: grid; grid-template-columns: repeat(auto-fit, minmax(240px, 1fr)); gap: 16px; margin-top: 16px; }
+.bar { display: grid; grid-template-columns: 110px 1fr 36px; align-items: center; gap: 6px; font-size: 12px; margin: 3px 0; }
+.bar .track { background: #eee; height: 12px; border-radius: 3px; }
+.bar .fill { height: 12px; border-radius: 3px; background: #546e7a; }
+.fill.critical, .badge.critical { background: var(--critical); }
+.fill.error, .badge.error { background: var(--error); }
+.fill.warning, .badge.warning { background: var(--warning); }
+.fill.info, .badge.info { background: var(--info); }
+.fill.hint, .badge.hint { background: var(--hint); }
+.badge { color: #fff; border-radius: 3px; padding: 1px 6px; font-size: 11px; font-weight: bold; }
+.tree, .tree ul { list-style: none; padding-left: 14px; margin: 0; font-size: 13px; }
+.tree { padding-left: 0; }
+.tree summary { cursor: pointer; }
+.tree .file { cursor: pointer; }
+.tree .file:hover, .tree .file.selected { text-decoration: underline; color: #1565c0; }
+.count { color: #666; font-size: 11px; }
+.count.crit { color: var(--critical); font-weight: bold; }
+.filters { display: flex; flex-wrap: wrap; gap: 8px; margin-bottom: 12px; }
+.filters select, .filters input { padding: 4px 6px; font-size: 13px; }
+.issue { border: 1px solid var(--border); border-left: 4px solid var(--hint); border-radius: 4px; padding: 10px 12px; margin-bottom: 10px; }
+.issue.critical { border-left-color: var(--critical); }
+.issue.error { border-left-color: var(--error); }
+.issue.warning { border-left-color: var(--warning); }
+.issue.info { border-left-color: var(--info); }
+.issue h3 { font-size: 14px; margin: 0 0 6px; }
+.issue .loc { font-family: Consolas, Monaco, monospace; font-size: 12px; color: #555; }
+.issue .tags { font-size: 12px; color: #555; margin: 4px 0; }
+.issue p { margin: 6px 0; font-size: 13px; }
+table.code { border-collapse: collapse; width: 100%; font-family: Consolas, Monaco, monospace; font-size: 12px; background: #f6f8fa; margin: 6px 0; }
+table.code td { padding: 0 8px; white-space: pre; vertical-align: top; }
+table.code td.ln { color: #999; text-align: right; user-select: none; width: 1%; }
+table.code tr.hl { background: #fff3c4; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; font-size: 12px; }
+table.grid { border-collapse: collapse; width: 100%; font-size: 13px; }
+table.grid th, table.grid td { border: 1px solid var(--border); padding: 4px 8px; text-align: left; }
+table.grid th { background: #f2f2f2; }
+.hidden { display: none; }
+.empty { color: #666; font-style: italic; }
+@media (max-width: 900px) { main { grid-template-columns: 1fr; } }
+</style>
+</head>
+<body>
+<header>
+<h1>Code Review Report</h1>
+<div class="meta">
+{{with .Result.Event.RepoFullName}}{{.}} &middot; {{end}}{{with .Result.Event.BaseCommit}}{{.}}..{{end}}{{.Result.Event.HeadCommit}}
+&middot; {{.Generated}} &middot; report {{.Result.ID}}
+</div>
+</header>
+<main>
+<aside>
+<h2>Files</h2>
+{{if .Tree}}<ul class="tree">{{template "tree" .Tree}}</ul>
+<p><a href="#" id="clear-file" class="hidden">Show all files</a></p>
+{{else}}<p class="empty">No findings in files.</p>{{end}}
+</aside>
+<div>
+<section>
+<h2>Summary</h2>
+<div class="cards">
+<div class="card"><div class="n">{{.Active}}</div><div class="l">Active issues</div></div>
+{{range .Severities}}<div class="card"><div class="n">{{.Count}}</div><div class="l">{{.Label}}</div></div>
+{{end}}<div class="card"><div class="n">{{.Result.Summary.SuppressedCount}}</div><div class="l">Suppressed</div></div>
+<div class="card"><div class="n">{{.Result.Summary.FileCount}}</div><div class="l">Files analyzed</div></div>
+{{with .Result.QualityGate}}<div class="card"><div class="n">{{.Status}}</div><div class="l">Quality gate</div></div>{{end}}
+{{with .Result.Risk}}<div class="card"><div class="n">{{printf "%.0f" .Score}}</div><div class="l">Risk ({{.Level}})</div></div>{{end}}
+</div>
+<div class="charts">
+<div><h2>By severity</h2>{{range .Severities}}<div class="bar"><span>{{.Label}}</span><div class="track"><div class="fill {{.Class}}" style="width: {{.Percent}}%"></div></div><span>{{.Count}}</span></div>{{end}}</div>
+<div><h2>By type</h2>{{range .Types}}<div class="bar"><span>{{.Label}}</span><div class="track"><div class="fill" style="width: {{.Percent}}%"></div></div><span>{{.Count}}</span></div>{{else}}<p class="empty">None</p>{{end}}</div>
+<div><h2>By tool</h2>{{range .Tools}}<div class="bar"><span>{{.Label}}</span><div class="track"><div class="fill" style="width: {{.Percent}}%"></div></div><span>{{.Count}}</span></div>{{else}}<p class="empty">None</p>{{end}}</div>
+</div>
+</section>
+<section>
+<h2>Findings</h2>
+<div class="filters">
+<select id="f-severity"><option value="">All severities</option>{{range .Severities}}<option value="{{.Class}}">{{.Label}}</option>{{end}}</select>
+<select id="f-type"><option value="">All types</option>{{range .TypeNames}}<option value="{{.}}">{{.}}</option>{{end}}</select>
+<select id="f-tool"><option value="">All tools</option>{{range .ToolNames}}<option value="{{.}}">{{.}}</option>{{end}}</select>
+<input id="f-text" type="search" placeholder="Search findings">
+<span id="f-count" class="count"></span>
+</div>
+{{range .Issues}}<div class="issue {{.SeverityCSS}}" data-severity="{{.SeverityCSS}}" data-type="{{.Type}}" data-tool="{{.Tool}}" data-file="{{.File}}">
+<h3>{{.Index}}. <span class="badge {{.SeverityCSS}}">{{.Severity}}</span> {{.Title}}</h3>
+<div class="loc">{{.File}}{{if .Line}}:{{.Line}}{{end}}{{if .Column}}:{{.Column}}{{end}}</div>
+<div class="tags">{{.Tool}}{{with .RuleID}} &middot; {{.}}{{end}}{{with .URL}} &middot; <a href="{{.}}" target="_blank" rel="noopener">docs</a>{{end}} &middot; {{.Type}}{{with .Lifecycle}} &middot; {{.}}{{end}}{{with .Owners}} &middot; owners: {{range $i, $o := .}}{{if $i}}, {{end}}{{$o}}{{end}}{{end}}</div>
+{{with .Description}}<p>{{.}}</p>{{end}}
+{{with .AIJustification}}<p><strong>AI justification:</strong> {{.}}</p>{{end}}
+{{if .Snippet}}<table class="code">{{range .Snippet}}<tr{{if .Highlight}} class="hl"{{end}}><td class="ln">{{if .Number}}{{.Number}}{{end}}</td><td>{{.Text}}</td></tr>{{end}}</table>{{end}}
+{{with .Fix}}<p><strong>Suggested fix:</strong></p><pre>{{.}}</pre>{{end}}
+</div>
+{{else}}<p class="empty">No active findings.</p>
+{{end}}
+</section>
+{{if .Suppressed}}<section>
+<h2>Suppression audit</h2>
+<table class="grid"><tr><th>File</th><th>Line</th><th>Tool</th><th>Rule</th><th>Justification</th></tr>
+{{range .Suppressed}}<tr><td>{{.File}}</td><td>{{.Line}}</td><td>{{.Tool}}</td><td>{{.RuleID}}</td><td>{{with .Suppression}}{{.Justification}}{{end}}</td></tr>
+{{end}}</table>
+</section>{{end}}
+{{if .GraphSource}}<section>
+<h2>Dependency graph</h2>
+{{range .Graph}}<h3>{{.Name}} ({{len .Edges}})</h3>
+<table class="grid"><tr><th>From</th><th>To</th><th>Kind</th></tr>{{range .Edges}}<tr><td>{{.From}}</td><td>{{.To}}</td><td>{{.Kind}}</td></tr>{{end}}</table>
+{{end}}<details><summary>Mermaid source</summary><pre>{{.GraphSource}}</pre></details>
+</section>{{end}}
+</div>
+</main>
+<script>
+(function () {
+  var issues = Array.prototype.slice.call(document.querySelectorAll(".issue"));
+  var sev = document.getElementById("f-severity"), typ = document.getElementById("f-type"),
+      tool = document.getElementById("f-tool"), text = document.getElementById("f-text"),
+      count = document.getElementById("f-count"), clear = document.getElementById("clear-file");
+  var file = "";
+  function apply() {
+    var q = text.value.toLowerCase(), shown = 0;
+    issues.forEach(function (el) {
+      var d = el.dataset;
+      var ok = (!sev.value || d.severity === sev.value) && (!typ.value || d.type === typ.value) &&
+        (!tool.value || d.tool === tool.value) &&
+        (!file || d.file === file || d.file.indexOf(file + "/") === 0) &&
+        (!q || el.textContent.toLowerCase().indexOf(q) >= 0);
+      el.classList.toggle("hidden", !ok);
+      if (ok) { shown++; }
+    });
+    count.textContent = shown + " of " + issues.length + " shown";
+    if (clear) { clear.classList.toggle("hidden", !file); }
+  }
+  [sev, typ, tool].forEach(function (el) { el.addEventListener("change", apply); });
+  text.addEventListener("input", apply);
+  document.querySelectorAll(".tree [data-path]").forEach(function (el) {
+    el.addEventListener("click", function (e) {
+      e.preventDefault();
+      document.querySelectorAll(".tree .selected").forEach(function (s) { s.classList.remove("selected"); });
+      el.classList.add("selected");
+      file = el.dataset.path;
+      apply();
+    });
+  });
+  if (clear) {
+    clear.addEventListener("click", function (e) {
+      e.preventDefault();
+      document.querySelectorAll(".tree .selected").forEach(function (s) { s.classList.remove("selected"); });
+      file = "";
+      apply();
+    });
+  }
+  apply();
+})();
+</script>
+</body>
+</html>
+{{define "tree"}}{{range .}}{{if .IsFile}}<li><span class="file" data-path="{{.Path}}">{{.Name}}</span> <span class="count{{if .Critical}} crit{{end}}">{{.Count}}</span></li>
+{{else}}<li><details open><summary><span data-path="{{.Path}}">{{.Name}}/</span> <span class="count{{if .Critical}} crit{{end}}">{{.Count}}</span></summary><ul>{{template "tree" .Children}}</ul></details></li>
+{{end}}{{end}}{{end}}`
@@ -0,0 +1,406 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/euclidstellar/gollora/internal/models"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+    pdfMargin     = 15.0
+    pdfLineHeight = 5.0
+    pdfCodeHeight = 4.0
+)
+
+// pdfSeverityColors matches the severity colors of the HTML report.
+var pdfSeverityColors = map[models.IssueSeverity][3]int{
+    models.Critical: {139, 0, 0},
+    models.Error:    {211, 47, 47},
+    models.Warning:  {249, 168, 37},
+    models.Info:     {25, 118, 210},
+    models.Hint:     {120, 144, 156},
+}
+
+// pdfSection is one table of contents entry.
+type pdfSection struct {
+    key   string
+    title string
+}
+
+// FormatToPDF renders the result as a PDF report in pure Go: a table of
+// contents, a summary table, one section per severity with source snippets
+// read from repoPath in monospace, and a suppression audit. Every page has a
+// header with the repository, commit and date and a numbered footer.
+func FormatToPDF(result *models.AnalysisResult, repoPath, outputFile string) error {
+    report := buildHTMLReport(result, repoPath)
+    sections := pdfSections(report)
+
+    // The contents lists page numbers, which are only known after the body
+    // is laid out. The first pass records them; the contents occupy the same
+    // space in both passes, so the numbers stay valid in the second.
+    pages := make(map[string]int)
+    if _, err := renderPDF(report, sections, pages); err != nil {
+        return err
+    }
+    pdf, err := renderPDF(report, sections, pages)
+    if err != nil {
+        return err
+    }
+
+    if err := pdf.OutputFileAndClose(outputFile); err != nil {
+        return fmt.Errorf("failed to write PDF file: %v", err)
+    }
+    return nil
+}
+
+func pdfSections(report *htmlReport) []pdfSection {
+    sections := []pdfSection{{key: "summary", title: "Summary"}}
+    for _, bar := range report.Severities {
+        if bar.Count > 0 {
+            sections = append(sections, pdfSection{key: bar.Class, title: fmt.Sprintf("%s findings (%d)", bar.Label, bar.Count)})
+        }
+    }
+    if len(report.Suppressed) > 0 {
+        sections = append(sections, pdfSection{key: "suppressed", title: fmt.Sprintf("Suppression audit (%d)", len(report.Suppressed))})
+    }
+    if len(report.Graph) > 0 {
+        sections = append(sections, pdfSection{key: "dependencies", title: "Dependency graph"})
+    }
+    return sections
+}
+
+type pdfWriter struct {
+    *fpdf.Fpdf
+    tr    func(string) string
+    width float64
+}
+
+func renderPDF(report *htmlReport, sections []pdfSection, pages map[string]int) (*fpdf.Fpdf, error) {
+    result := report.Result
+    pdf := fpdf.New("P", "mm", "A4", "")
+    w := &pdfWriter{Fpdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+    pageWidth, _ := pdf.GetPageSize()
+    w.width = pageWidth - 2*pdfMargin
+
+    pdf.SetMargins(pdfMargin, 20, pdfMargin)
+    pdf.SetAutoPageBreak(true, 18)
+    pdf.AliasNbPages("")
+    pdf.SetTitle("Code Review Report "+result.ID, true)
+    pdf.SetCreator("Gollora", true)
+    pdf.SetCreationDate(result.AnalyzedAt)
+
+    header := pdfHeaderText(report)
+    pdf.SetHeaderFunc(func() {
+        pdf.SetY(8)
+        pdf.SetFont("Helvetica", "", 8)
+        pdf.SetTextColor(100, 100, 100)
+        pdf.CellFormat(w.width, 5, w.tr(header), "B", 1, "L", false, 0, "")
+        pdf.SetY(20)
+    })
+    pdf.SetFooterFunc(func() {
+        pdf.SetY(-12)
+        pdf.SetFont("Helvetica", "", 8)
+        pdf.SetTextColor(100, 100, 100)
+        pdf.CellFormat(w.width/2, 5, w.tr("Report "+result.ID), "T", 0, "L", false, 0, "")
+        pdf.CellFormat(w.width/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "T", 0, "R", false, 0, "")
+    })
+
+    links := make(map[string]int)
+    for _, s := range sections {
+        links[s.key] = pdf.AddLink()
+    }
+
+    pdf.AddPage()
+    w.heading("Code Review Report", 18)
+    w.text(header)
+    pdf.Ln(4)
+    w.heading("Contents", 13)
+    for _, s := range sections {
+        page := ""
+        if n := pages[s.key]; n > 0 {
+            page = fmt.Sprint(n)
+        }
+        pdf.SetFont("Helvetica", "", 10)
+        pdf.SetTextColor(21, 101, 192)
+        pdf.CellFormat(w.width-15, 6, w.tr(s.title), "", 0, "L", false, links[s.key], "")
+        pdf.SetTextColor(0, 0, 0)
+        pdf.CellFormat(15, 6, page, "", 1, "R", false, links[s.key], "")
+    }
+
+    start := func(key, title string) {
+        pdf.AddPage()
+        pages[key] = pdf.PageNo()
+        pdf.SetLink(links[key], -1, -1)
+        pdf.Bookmark(title, 0, -1)
+        w.heading(title, 14)
+    }
+
+    start("summary", "Summary")
+    w.summaryTable(report)
+
+    for _, bar := range report.Severities {
+        if bar.Count == 0 {
+            continue
+        }
+        start(bar.Class, fmt.Sprintf("%s findings (%d)", bar.Label, bar.Count))
+        for _, issue := range report.Issues {
+            if string(issue.Severity) == bar.Label {
+                w.issue(issue)
+            }
+        }
+    }
+
+    if len(report.Suppressed) > 0 {
+        start("suppressed", fmt.Sprintf("Suppression audit (%d)", len(report.Suppressed)))
+        w.table([]string{"File", "Line", "Tool", "Rule", "Justification"}, []float64{0.32, 0.08, 0.15, 0.15, 0.30}, suppressionRows(report.Suppressed))
+    }
+
+    if len(report.Graph) > 0 {
+        start("dependencies", "Dependency graph")
+        for _, group := range report.Graph {
+            w.subheading(fmt.Sprintf("%s (%d)", group.Name, len(group.Edges)))
+            rows := make([][]string, 0, len(group.Edges))
+            for _, edge := range group.Edges {
+                rows = append(rows, []string{edge.From, edge.To, edge.Kind})
+            }
+            w.table([]string{"From", "To", "Kind"}, []float64{0.35, 0.45, 0.2}, rows)
+            pdf.Ln(3)
+        }
+    }
+
+    if err := pdf.Error(); err != nil {
+        return nil, fmt.Errorf("failed to render PDF: %v", err)
+    }
+    return pdf, nil
+}
+
+func pdfHeaderText(report *htmlReport) string {
+    event := report.Result.Event
+    var parts []string
+    if event.RepoFullName != "" {
+        parts = append(parts, event.RepoFullName)
+    }
+    if event.Branch != "" {
+        parts = append(parts, event.Branch)
+    }
+    commit := shortCommit(event.HeadCommit)
+    if event.BaseCommit != "" {
+        commit = shortCommit(event.BaseCommit) + ".." + commit
+    }
+    if commit != "" {
+        parts = append(parts, commit)
+    }
+    parts = append(parts, report.Generated)
+    return strings.Join(parts, "  |  ")
+}
+
+func shortCommit(sha string) string {
+    if len(sha) > 12 {
+        return sha[:12]
+    }
+    return sha
+}
+
+func (w *pdfWriter) heading(text string, size float64) {
+    w.SetFont("Helvetica", "B", size)
+    w.SetTextColor(38, 50, 56)
+    w.MultiCell(w.width, size*0.5, w.tr(text), "", "L", false)
+    w.Ln(2)
+}
+
+func (w *pdfWriter) subheading(text string) {
+    w.SetFont("Helvetica", "B", 11)
+    w.SetTextColor(38, 50, 56)
+    w.MultiCell(w.width, 6, w.tr(text), "", "L", false)
+    w.Ln(1)
+}
+
+func (w *pdfWriter) text(text string) {
+    w.SetFont("Helvetica", "", 10)
+    w.SetTextColor(0, 0, 0)
+    w.MultiCell(w.width, pdfLineHeight, w.tr(text), "", "L", false)
+}
+
+func (w *pdfWriter) summaryTable(report *htmlReport) {
+    result := report.Result
+    rows := [][]string{{"Active issues", fmt.Sprint(report.Active)}}
+    for _, bar := range report.Severities {
+        rows = append(rows, []string{bar.Label, fmt.Sprint(bar.Count)})
+    }
+    rows = append(rows,
+        []string{"Suppressed", fmt.Sprint(result.Summary.SuppressedCount)},
+        []string{"Files analyzed", fmt.Sprint(result.Summary.FileCount)},
+    )
+    if result.QualityGate != nil {
+        rows = append(rows, []string{"Quality gate", string(result.QualityGate.Status)})
+    }
+    if result.Risk != nil {
+        rows = append(rows, []string{"Risk score", fmt.Sprintf("%.1f (%s)", result.Risk.Score, result.Risk.Level)})
+    }
+    if result.History != nil {
+        rows = append(rows, []string{"New / fixed", fmt.Sprintf("%d / %d", result.History.New+result.History.Reintroduced, result.History.Fixed)})
+    }
+    w.table([]string{"Metric", "Value"}, []float64{0.5, 0.5}, rows)
+
+    for _, group := range []struct {
+        title string
+        bars  []htmlBar
+    }{{"By type", report.Types}, {"By tool", report.Tools}} {
+        if len(group.bars) == 0 {
+            continue
+        }
+        w.Ln(4)
+        w.subheading(group.title)
+        rows := make([][]string, 0, len(group.bars))
+        for _, bar := range group.bars {
+            rows = append(rows, []string{bar.Label, fmt.Sprint(bar.Count)})
+        }
+        w.table([]string{"Name", "Issues"}, []float64{0.5, 0.5}, rows)
+    }
+}
+
+// table draws a bordered table; widths are fractions of the text width.
+// Cells are truncated to fit on one line.
+func (w *pdfWriter) table(headers []string, widths []float64, rows [][]string) {
+    w.SetFont("Helvetica", "B", 9)
+    w.SetFillColor(242, 242, 242)
+    w.SetTextColor(0, 0, 0)
+    for i, h := range headers {
+        w.CellFormat(widths[i]*w.width, 6, w.tr(h), "1", 0, "L", true, 0, "")
+    }
+    w.Ln(-1)
+
+    w.SetFont("Helvetica", "", 9)
+    for _, row := range rows {
+        for i, cell := range row {
+            cw := widths[i] * w.width
+            w.CellFormat(cw, 6, w.fit(w.tr(cell), cw-2), "1", 0, "L", false, 0, "")
+        }
+        w.Ln(-1)
+    }
+}
+
+func (w *pdfWriter) issue(issue htmlIssue) {
+    _, pageHeight := w.GetPageSize()
+    if w.GetY() > pageHeight-60 {
+        w.AddPage()
+    }
+
+    color := pdfSeverityColors[issue.Severity]
+    w.SetFillColor(color[0], color[1], color[2])
+    w.Rect(pdfMargin, w.GetY(), 1.2, 6, "F")
+    w.SetX(pdfMargin + 3)
+    w.SetFont("Helvetica", "B", 10)
+    w.SetTextColor(0, 0, 0)
+    w.MultiCell(w.width-3, pdfLineHeight, w.tr(fmt.Sprintf("%d. %s", issue.Index, issue.Title)), "", "L", false)
+
+    location := issue.File
+    if issue.Line > 0 {
+        location = fmt.Sprintf("%s:%d", location, issue.Line)
+    }
+    meta := []string{location, issue.Tool}
+    if issue.RuleID != "" {
+        meta = append(meta, issue.RuleID)
+    }
+    if issue.Lifecycle != "" {
+        meta = append(meta, string(issue.Lifecycle))
+    }
+    if len(issue.Owners) > 0 {
+        meta = append(meta, "owners: "+strings.Join(issue.Owners, ", "))
+    }
+    w.SetX(pdfMargin + 3)
+    w.SetFont("Courier", "", 8)
+    w.SetTextColor(90, 90, 90)
+    w.MultiCell(w.width-3, 4, w.tr(strings.Join(meta, "  |  ")), "", "L", false)
+
+    if issue.Description != "" && issue.Description != issue.Title {
+        w.SetX(pdfMargin + 3)
+        w.SetFont("Helvetica", "", 9)
+        w.SetTextColor(0, 0, 0)
+        w.MultiCell(w.width-3, pdfLineHeight, w.tr(issue.Description), "", "L", false)
+    }
+    if issue.AIJustification != "" {
+        w.SetX(pdfMargin + 3)
+        w.SetFont("Helvetica", "I", 9)
+        w.MultiCell(w.width-3, pdfLineHeight, w.tr("AI justification: "+issue.AIJustification), "", "L", false)
+    }
+
+    if len(issue.Snippet) > 0 {
+        w.Ln(1)
+        w.code(issue.Snippet)
+    }
+    if issue.Fix != "" {
+        w.Ln(1)
+        w.SetX(pdfMargin + 3)
+        w.SetFont("Helvetica", "B", 9)
+        w.SetTextColor(0, 0, 0)
+        w.CellFormat(w.width-3, pdfLineHeight, "Suggested fix:", "", 1, "L", false, 0, "")
+        var lines []htmlLine
+        for _, text := range strings.Split(strings.TrimRight(issue.Fix, "\n"), "\n") {
+            lines = append(lines, htmlLine{Text: text})
+        }
+        w.code(lines)
+    }
+    w.Ln(4)
+}
+
+// code draws source lines in monospace on a shaded block, highlighting the
+// flagged line. Long lines are truncated rather than wrapped so line numbers
+// stay aligned.
+func (w *pdfWriter) code(lines []htmlLine) {
+    w.SetFont("Courier", "", 8)
+    w.SetTextColor(0, 0, 0)
+    numberWidth := 10.0
+    textWidth := w.width - 3 - numberWidth
+    for _, line := range lines {
+        if line.Highlight {
+            w.SetFillColor(255, 243, 196)
+        } else {
+            w.SetFillColor(246, 248, 250)
+        }
+        number := ""
+        if line.Number > 0 {
+            number = fmt.Sprint(line.Number)
+        }
+        w.SetX(pdfMargin + 3)
+        w.SetTextColor(150, 150, 150)
+        w.CellFormat(numberWidth, pdfCodeHeight, number, "", 0, "R", true, 0, "")
+        w.SetTextColor(0, 0, 0)
+        text := strings.ReplaceAll(line.Text, "\t", "    ")
+        w.CellFormat(textWidth, pdfCodeHeight, " "+w.fit(w.tr(text), textWidth-3), "", 1, "L", true, 0, "")
+    }
+}
+
+// fit truncates text to the given width in the current font.
+func (w *pdfWriter) fit(text string, width float64) string {
+    if w.GetStringWidth(text) <= width {
+        return text
+    }
+    for len(text) > 0 && w.GetStringWidth(text+"...") > width {
+        text = text[:len(text)-1]
+    }
+    return text + "..."
+}
+
+func suppressionRows(issues []htmlIssue) [][]string {
+    sorted := append([]htmlIssue(nil), issues...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        if sorted[i].File != sorted[j].File {
+            return sorted[i].File < sorted[j].File
+        }
+        return sorted[i].Line < sorted[j].Line
+    })
+
+    rows := make([][]string, 0, len(sorted))
+    for _, issue := range sorted {
+        justification := ""
+        if issue.Suppression != nil {
+            justification = issue.Suppression.Justification
+        }
+        rows = append(rows, []string{issue.File, fmt.Sprint(issue.Line), issue.Tool, issue.RuleID, justification})
+    }
+    return rows
+}